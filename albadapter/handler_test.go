@@ -0,0 +1,137 @@
+package albadapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_HappyPath(t *testing.T) {
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Add("Key1", "val1")
+		w.Header().Add("Key1", "val2")
+		_, _ = fmt.Fprintf(w, "Hello %s!", r.URL.Query().Get("name"))
+	})
+
+	t.Run("SingleValue", func(t *testing.T) {
+		res, err := Handler(mux)(context.Background(), Request{
+			HTTPMethod:            "GET",
+			Path:                  "/",
+			QueryStringParameters: map[string]string{"name": "World"},
+			Headers:               map[string]string{"host": "example.com"},
+		})
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t,
+			&Response{
+				StatusCode:        200,
+				StatusDescription: "200 OK",
+				Headers:           map[string]string{"Content-Type": "text/plain", "Key1": "val1"},
+				Body:              "Hello World!",
+			},
+			res)
+	})
+
+	t.Run("MultiValue", func(t *testing.T) {
+		res, err := Handler(mux)(context.Background(), Request{
+			HTTPMethod:                      "GET",
+			Path:                            "/",
+			MultiValueQueryStringParameters: map[string][]string{"name": {"World"}},
+			MultiValueHeaders:               map[string][]string{"host": {"example.com"}},
+		})
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t,
+			&Response{
+				StatusCode:        200,
+				StatusDescription: "200 OK",
+				MultiValueHeaders: map[string][]string{"Content-Type": {"text/plain"}, "Key1": {"val1", "val2"}},
+				Body:              "Hello World!",
+			},
+			res)
+	})
+}
+
+func TestHandler_HealthCheck(t *testing.T) {
+	req := Request{
+		HTTPMethod: "GET",
+		Path:       "/",
+		Headers:    map[string]string{"user-agent": "ELB-HealthChecker/2.0"},
+	}
+
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		res, err := Handler(mux)(context.Background(), req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, 404, res.StatusCode)
+	})
+
+	t.Run("WithHealthCheckHandler", func(t *testing.T) {
+		res, err := Handler(mux, WithHealthCheckHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})))(context.Background(), req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, &Response{StatusCode: 204, StatusDescription: "204 No Content", Headers: map[string]string{}}, res)
+	})
+}
+
+func TestHandler_TransformRequestError(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	})
+
+	res, err := Handler(h, WithErrorHandler(func(ctx context.Context, err error) (*Response, error) {
+		return &Response{StatusCode: 400, StatusDescription: "400 Bad Request", Body: err.Error()}, nil
+	}))(context.Background(), Request{IsBase64Encoded: true, Body: "blarg"})
+
+	if !assert.NoError(t, err, "error handler error should be returned") {
+		return
+	}
+	assert.Equal(t, &Response{StatusCode: 400, StatusDescription: "400 Bad Request", Body: "failed to decode body: illegal base64 data at input byte 4"}, res)
+}
+
+func TestHandlerFunc_Invoke(t *testing.T) {
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s %s", r.Method, r.URL.Path)
+	}))
+
+	res, err := h.Invoke(context.Background(), []byte(`{
+		"requestContext": {"elb": {"targetGroupArn": "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/lambda/abcdef"}},
+		"httpMethod": "GET",
+		"path": "/my/path",
+		"queryStringParameters": {},
+		"headers": {"host": "example.com"},
+		"body": "",
+		"isBase64Encoded": false
+	}`))
+	if !assert.NoError(t, err, "failed to invoke handler") {
+		return
+	}
+
+	assert.JSONEq(t,
+		`{
+			"statusCode": 200,
+			"statusDescription": "200 OK",
+			"headers": {"Content-Type": "text/plain; charset=utf-8"},
+			"body": "GET /my/path",
+			"isBase64Encoded": false
+		}`,
+		string(res))
+}
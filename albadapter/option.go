@@ -0,0 +1,73 @@
+package albadapter
+
+import (
+	"context"
+	"net/http"
+)
+
+// Option configures the behaviour of Handler.
+type Option interface {
+	apply(*options)
+}
+
+// optionFunc is an Option implemented by a function.
+type optionFunc func(*options)
+
+func (f optionFunc) apply(o *options) {
+	f(o)
+}
+
+type options struct {
+	errorHandler func(context.Context, error) (*Response, error)
+	encRes       EncodingPolicy
+	maxBodySize  int
+
+	healthCheckHandler http.Handler
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		errorHandler: func(_ context.Context, err error) (*Response, error) {
+			return nil, err
+		},
+		encRes:      DefaultEncodingPolicy,
+		maxBodySize: DefaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+	return o
+}
+
+// WithErrorHandler configures how request and response transformation errors are handled.
+// The returned *Response and error are returned from the Lambda handler as-is.
+// By default the transformation error is returned which results in a Lambda invocation error.
+func WithErrorHandler(fn func(ctx context.Context, err error) (*Response, error)) Option {
+	return optionFunc(func(o *options) {
+		o.errorHandler = fn
+	})
+}
+
+// WithEncoding configures which responses are base64 encoded. See TransformResponse for details.
+// Defaults to DefaultEncodingPolicy.
+func WithEncoding(encRes EncodingPolicy) Option {
+	return optionFunc(func(o *options) {
+		o.encRes = encRes
+	})
+}
+
+// WithMaxBodySize configures the maximum response body size. A response body which exceeds it is handled as an error
+// wrapping ErrBodyTooLarge. A maxBodySize <= 0 means no limit. Defaults to DefaultMaxBodySize.
+func WithMaxBodySize(maxBodySize int) Option {
+	return optionFunc(func(o *options) {
+		o.maxBodySize = maxBodySize
+	})
+}
+
+// WithHealthCheckHandler configures a handler for load balancer health checks. By default health checks are served
+// by the handler passed to Handler like any other request.
+func WithHealthCheckHandler(h http.Handler) Option {
+	return optionFunc(func(o *options) {
+		o.healthCheckHandler = h
+	})
+}
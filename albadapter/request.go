@@ -0,0 +1,132 @@
+// Package albadapter transforms Application Load Balancer Lambda target requests and responses to Go HTTP requests
+// and responses.
+package albadapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"harrisonhjones.com/go-apigw-http-adapter/internal/request"
+)
+
+// healthCheckUserAgent is the User-Agent of ALB health check requests.
+const healthCheckUserAgent = "ELB-HealthChecker/2.0"
+
+// Request contains all relevant Application Load Balancer request data needed to transform it into a http.Request.
+// Depending on the target group configuration either Headers and QueryStringParameters or MultiValueHeaders and
+// MultiValueQueryStringParameters are set, never both.
+// Unlike API Gateway, query string parameters are not URL decoded by the load balancer.
+// https://docs.aws.amazon.com/elasticloadbalancing/latest/application/lambda-functions.html
+type Request struct {
+	HTTPMethod                      string              `json:"httpMethod"`
+	Path                            string              `json:"path"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters,omitempty"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters,omitempty"`
+	Headers                         map[string]string   `json:"headers,omitempty"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders,omitempty"`
+	RequestContext                  RequestContext      `json:"requestContext"`
+	Body                            string              `json:"body"`
+	IsBase64Encoded                 bool                `json:"isBase64Encoded"`
+}
+
+// RequestContext contains all relevant data needed for Request transformation.
+type RequestContext struct {
+	ELB RequestContextELB `json:"elb"`
+}
+
+// RequestContextELB identifies the target group which received the request.
+type RequestContextELB struct {
+	TargetGroupARN string `json:"targetGroupArn"`
+}
+
+// IsMultiValue reports whether the target group has multi-value headers enabled. The Response must use the same mode.
+func (r *Request) IsMultiValue() bool {
+	return r.MultiValueHeaders != nil || r.MultiValueQueryStringParameters != nil
+}
+
+// IsHealthCheck reports whether the Request is a load balancer health check.
+func (r *Request) IsHealthCheck() bool {
+	return r.header("User-Agent") == healthCheckUserAgent
+}
+
+// header returns the first value of the header named key, regardless of the header mode and key case.
+func (r *Request) header(key string) string {
+	for k, v := range r.Headers {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	for k, v := range r.MultiValueHeaders {
+		if strings.EqualFold(k, key) && len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// rawQuery returns the query string. The parameters are already percent-encoded so they are joined as-is.
+// Keys are sorted as their original order is not available; the order of multiple values is preserved.
+func (r *Request) rawQuery() string {
+	params := r.MultiValueQueryStringParameters
+	if params == nil {
+		params = map[string][]string{}
+		for k, v := range r.QueryStringParameters {
+			params[k] = []string{v}
+		}
+	}
+
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, k := range keys {
+		for _, v := range params[k] {
+			parts = append(parts, k+"="+v)
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// TransformRequest transforms a *Request to a *http.Request.
+// The URL scheme is taken from the X-Forwarded-Proto header and the host from the Host header.
+// A non-nil error will be returned if the *Request is nil or if the transformation fails.
+// The *Request will not be mutated during transformation.
+func TransformRequest(ctx context.Context, req *Request) (*http.Request, error) {
+	if req == nil {
+		return nil, fmt.Errorf("req cannot be nil")
+	}
+	body, err := request.Body(req.Body, req.IsBase64Encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	scheme := req.header("X-Forwarded-Proto")
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	u, err := url.Parse(scheme + "://" + req.header("Host") + req.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse url: %v", err)
+	}
+	u.RawQuery = req.rawQuery()
+
+	hReq, err := http.NewRequest(req.HTTPMethod, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new http request: %v", err)
+	}
+
+	hReq = hReq.WithContext(ctx)
+
+	request.AddHeaders(hReq.Header, req.Headers, nil)
+	request.AddMultiValueHeaders(hReq.Header, req.MultiValueHeaders)
+
+	return hReq, nil
+}
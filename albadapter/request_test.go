@@ -0,0 +1,153 @@
+package albadapter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformRequest_SingleValue(t *testing.T) {
+	req := Request{
+		HTTPMethod: "POST",
+		Path:       "/my/path",
+		QueryStringParameters: map[string]string{
+			"parameter1": "hello%20world", // FYI: The load balancer does not decode parameters.
+			"parameter2": "a%2Bb",
+		},
+		Headers: map[string]string{
+			"header1":           "value1",
+			"host":              "example.com",
+			"x-forwarded-proto": "https",
+			"cookie":            "cookie1=val1; cookie2=val2",
+		},
+		RequestContext: RequestContext{
+			ELB: RequestContextELB{TargetGroupARN: "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/lambda/abcdef"},
+		},
+	}
+
+	tstCtx := context.Background()
+
+	t.Run("NotEncoded", func(t *testing.T) {
+		req.Body = "Hello World!"
+		req.IsBase64Encoded = false
+
+		httpReq, err := TransformRequest(tstCtx, &req)
+
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, tstCtx, httpReq.Context())
+		assert.False(t, req.IsMultiValue())
+
+		assert.Equal(t,
+			http.Header{
+				"Cookie":            []string{"cookie1=val1; cookie2=val2"},
+				"Header1":           []string{"value1"},
+				"Host":              []string{"example.com"},
+				"X-Forwarded-Proto": []string{"https"},
+			},
+			httpReq.Header)
+
+		assert.Equal(t, "https://example.com/my/path?parameter1=hello%20world&parameter2=a%2Bb", httpReq.URL.String())
+		assert.Equal(t, "hello world", httpReq.URL.Query().Get("parameter1"))
+		assert.Equal(t, "a+b", httpReq.URL.Query().Get("parameter2"))
+
+		ck1, err := httpReq.Cookie("cookie1")
+		if !assert.NoError(t, err, "failed to get cookie1") {
+			return // ck1 is nil if err != nil so return early to prevent panics
+		}
+		assert.Equal(t, "val1", ck1.Value)
+
+		b, err := ioutil.ReadAll(httpReq.Body)
+		if !assert.NoError(t, err, "failed to read body") {
+			return
+		}
+
+		assert.Equal(t, []byte("Hello World!"), b)
+	})
+
+	t.Run("Encoded", func(t *testing.T) {
+		req.Body = "SGVsbG8gRW5jb2RlZCBXb3JsZCE=" // FYI: base64.StdEncoding.EncodeToString([]byte("Hello Encoded World!"))
+		req.IsBase64Encoded = true
+
+		httpReq, err := TransformRequest(tstCtx, &req)
+
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		b, err := ioutil.ReadAll(httpReq.Body)
+		if !assert.NoError(t, err, "failed to read body") {
+			return
+		}
+
+		assert.Equal(t, []byte("Hello Encoded World!"), b)
+	})
+
+	t.Run("IncorrectlyEncoded", func(t *testing.T) {
+		req.Body = "blarg"
+		req.IsBase64Encoded = true
+
+		_, err := TransformRequest(tstCtx, &req)
+
+		assert.EqualError(t, err, "failed to decode body: illegal base64 data at input byte 4")
+	})
+}
+
+func TestTransformRequest_MultiValue(t *testing.T) {
+	req := Request{
+		HTTPMethod: "GET",
+		Path:       "/my/path",
+		MultiValueQueryStringParameters: map[string][]string{
+			"parameter2": {"value"},
+			"parameter1": {"value2", "value%201"},
+		},
+		MultiValueHeaders: map[string][]string{
+			"header1": {"value1", "value2"},
+			"host":    {"example.com"},
+		},
+	}
+
+	httpReq, err := TransformRequest(context.Background(), &req)
+
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	assert.True(t, req.IsMultiValue())
+
+	assert.Equal(t,
+		http.Header{
+			"Header1": []string{"value1", "value2"},
+			"Host":    []string{"example.com"},
+		},
+		httpReq.Header)
+
+	// FYI: Without X-Forwarded-Proto the scheme defaults to http.
+	assert.Equal(t, "http://example.com/my/path?parameter1=value2&parameter1=value%201&parameter2=value", httpReq.URL.String())
+	assert.Equal(t, []string{"value2", "value 1"}, httpReq.URL.Query()["parameter1"])
+}
+
+func TestRequest_IsHealthCheck(t *testing.T) {
+	// FYI: Health check events carry no Host header and no query string parameters.
+	req := Request{
+		HTTPMethod:            "GET",
+		Path:                  "/health",
+		QueryStringParameters: map[string]string{},
+		Headers:               map[string]string{"user-agent": "ELB-HealthChecker/2.0"},
+	}
+
+	assert.True(t, req.IsHealthCheck())
+	assert.False(t, (&Request{Headers: map[string]string{"user-agent": "curl/7.64.1"}}).IsHealthCheck())
+	assert.True(t, (&Request{MultiValueHeaders: map[string][]string{"User-Agent": {"ELB-HealthChecker/2.0"}}}).IsHealthCheck())
+
+	httpReq, err := TransformRequest(context.Background(), &req)
+	if !assert.NoError(t, err, "failed to transform health check request") {
+		return
+	}
+	assert.Equal(t, "/health", httpReq.URL.Path)
+}
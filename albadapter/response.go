@@ -0,0 +1,60 @@
+package albadapter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"harrisonhjones.com/go-apigw-http-adapter/internal/response"
+)
+
+// Response configures the response to be returned by the Application Load Balancer for the request.
+// Only one of Headers or MultiValueHeaders is set, matching the mode of the Request.
+// https://docs.aws.amazon.com/elasticloadbalancing/latest/application/lambda-functions.html
+type Response struct {
+	StatusCode        int                 `json:"statusCode"`
+	StatusDescription string              `json:"statusDescription"`
+	Headers           map[string]string   `json:"headers,omitempty"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders,omitempty"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+}
+
+// TransformResponse transforms an http.Response to a Response.
+// The body is base64 encoded if encRes reports true for res, e.g. DefaultEncodingPolicy, or if it is not valid UTF-8.
+// A nil encRes only encodes bodies which are not valid UTF-8.
+// If multiValue is false only the first value of each header is returned, so only a single cookie can be set.
+// Use Request.IsMultiValue to determine the mode of the target group.
+func TransformResponse(res *http.Response, encRes EncodingPolicy, multiValue bool) (*Response, error) {
+	albRes := &Response{
+		StatusCode:        res.StatusCode,
+		StatusDescription: fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)),
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	albRes.Body, albRes.IsBase64Encoded, err = response.EncodeBody(body, encRes != nil && encRes(res), response.UTF8Auto)
+	if err != nil {
+		return nil, err
+	}
+
+	if multiValue {
+		albRes.MultiValueHeaders = map[string][]string{}
+		for k, v := range res.Header {
+			albRes.MultiValueHeaders[k] = v
+		}
+	} else {
+		albRes.Headers = map[string]string{}
+		for k, v := range res.Header {
+			if len(v) == 0 {
+				continue
+			}
+			albRes.Headers[k] = v[0]
+		}
+	}
+
+	return albRes, nil
+}
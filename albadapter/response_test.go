@@ -0,0 +1,133 @@
+package albadapter
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestRecorder(t *testing.T, body string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	http.SetCookie(recorder, &http.Cookie{Name: "cookie1-name", Value: "cookie1-value"})
+	http.SetCookie(recorder, &http.Cookie{Name: "cookie2-name", Value: "cookie2-value"})
+	recorder.Header().Add("key1", "val1")
+	recorder.Header().Add("key1", "val2")
+	recorder.Header().Add("key2", "val2")
+	recorder.WriteHeader(201)
+	_, err := recorder.WriteString(body)
+	assert.NoError(t, err, "failed to write string to test recorder")
+	return recorder
+}
+
+func TestTransformResponse_HappyPathSingleValue(t *testing.T) {
+	recorder := newTestRecorder(t, "Hello World!")
+
+	response, err := TransformResponse(recorder.Result(), nil, false)
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	assert.Equal(t,
+		&Response{
+			StatusCode:        201,
+			StatusDescription: "201 Created",
+			Headers: map[string]string{
+				"Key1":       "val1",
+				"Key2":       "val2",
+				"Set-Cookie": "cookie1-name=cookie1-value",
+			},
+			Body:            "Hello World!",
+			IsBase64Encoded: false,
+		},
+		response)
+}
+
+func TestTransformResponse_HappyPathMultiValue(t *testing.T) {
+	recorder := newTestRecorder(t, "Hello World!")
+
+	response, err := TransformResponse(recorder.Result(), nil, true)
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	assert.Equal(t,
+		&Response{
+			StatusCode:        201,
+			StatusDescription: "201 Created",
+			MultiValueHeaders: map[string][]string{
+				"Key1":       {"val1", "val2"},
+				"Key2":       {"val2"},
+				"Set-Cookie": {"cookie1-name=cookie1-value", "cookie2-name=cookie2-value"},
+			},
+			Body:            "Hello World!",
+			IsBase64Encoded: false,
+		},
+		response)
+}
+
+func TestTransformResponse_HappyPathEncoded(t *testing.T) {
+	recorder := newTestRecorder(t, "Hello Encoded World!")
+
+	response, err := TransformResponse(recorder.Result(), func(response *http.Response) bool {
+		// FYI: Shallow check that it is the same http.Response.
+		assert.Equal(t, "val1", response.Header.Get("key1"))
+		return true
+	}, true)
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	assert.Equal(t, "SGVsbG8gRW5jb2RlZCBXb3JsZCE=", response.Body)
+	assert.True(t, response.IsBase64Encoded)
+}
+
+func TestTransformResponse_EmptyHeader(t *testing.T) {
+	res := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": nil, "Key1": {"val1"}},
+		Body:       ioutil.NopCloser(strings.NewReader("Hello World!")),
+	}
+
+	response, err := TransformResponse(res, DefaultEncodingPolicy, false)
+	if !assert.NoError(t, err, "failed to transform response") {
+		return
+	}
+
+	assert.Equal(t, map[string]string{"Key1": "val1"}, response.Headers)
+}
+
+func TestTransformResponse_InvalidUTF8(t *testing.T) {
+	res := &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(strings.NewReader("a\xffb")),
+	}
+
+	response, err := TransformResponse(res, nil, false)
+	if !assert.NoError(t, err, "failed to transform response") {
+		return
+	}
+
+	assert.Equal(t, "Yf9i", response.Body)
+	assert.True(t, response.IsBase64Encoded)
+}
+
+func TestTransformResponse_BadBody(t *testing.T) {
+	_, err := TransformResponse(&http.Response{
+		Body: ioutil.NopCloser(&FailingReader{}),
+	}, nil, false)
+	assert.EqualError(t, err, "failed to read response body: boom")
+}
+
+type FailingReader struct{}
+
+func (f FailingReader) Read([]byte) (n int, err error) {
+	return 0, fmt.Errorf("boom")
+}
+
+var _ io.Reader = &FailingReader{}
@@ -0,0 +1,68 @@
+// Command apigw-local emulates API Gateway on a local port in front of an example handler which echoes the
+// transformed request. Every request is converted into an API Gateway event and passed through exactly the same
+// transformation code as it would be in Lambda.
+//
+// Usage:
+//
+//	apigw-local [-addr :8080] [-payload 2.0]
+//
+// To run your own handler locally use the localgw package in your own main package instead, e.g.:
+//
+//	http.ListenAndServe(":8080", localgw.HTTPAPI(httpadapter.Handler(mux)))
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+
+	"harrisonhjones.com/go-apigw-http-adapter/httpadapter"
+	"harrisonhjones.com/go-apigw-http-adapter/localgw"
+	"harrisonhjones.com/go-apigw-http-adapter/restadapter"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	payload := flag.String("payload", "2.0", `payload format version: "2.0" (HTTP API) or "1.0" (REST API)`)
+	flag.Parse()
+
+	var gw http.Handler
+	switch *payload {
+	case "2.0":
+		gw = localgw.HTTPAPI(httpadapter.Handler(http.HandlerFunc(echo)))
+	case "1.0":
+		gw = localgw.RESTAPI(restadapter.Handler(http.HandlerFunc(echo)))
+	default:
+		log.Fatalf("unsupported payload format version %q", *payload)
+	}
+
+	log.Printf("emulating payload format version %s on %s", *payload, *addr)
+	log.Fatal(http.ListenAndServe(*addr, gw))
+}
+
+// echo writes the transformed request back to the client.
+func echo(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	keys := make([]string, 0, len(r.Header))
+	for k := range r.Header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "%s %s\n", r.Method, r.URL)
+	for _, k := range keys {
+		for _, v := range r.Header[k] {
+			fmt.Fprintf(w, "%s: %s\n", k, v)
+		}
+	}
+	fmt.Fprintf(w, "\n%s", body)
+}
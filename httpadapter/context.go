@@ -0,0 +1,51 @@
+package httpadapter
+
+import "context"
+
+type contextKey int
+
+const (
+	requestContextKey contextKey = iota
+	stageVariablesKey
+)
+
+func withRequestContext(ctx context.Context, reqCtx RequestContext) context.Context {
+	return context.WithValue(ctx, requestContextKey, reqCtx)
+}
+
+// RequestContextFrom returns the RequestContext of the Request from the context of a transformed http.Request.
+func RequestContextFrom(ctx context.Context) (RequestContext, bool) {
+	reqCtx, ok := ctx.Value(requestContextKey).(RequestContext)
+	return reqCtx, ok
+}
+
+// AuthorizerFrom returns the authorizer data from the context of a transformed http.Request. It returns false if the
+// request was not authorized by an authorizer.
+func AuthorizerFrom(ctx context.Context) (*RequestContextAuthorizer, bool) {
+	reqCtx, ok := RequestContextFrom(ctx)
+	if !ok || reqCtx.Authorizer == nil {
+		return nil, false
+	}
+	return reqCtx.Authorizer, true
+}
+
+// JWTClaimsFrom returns the JWT claims from the context of a transformed http.Request. It returns false if the request
+// was not authorized by a JWT authorizer.
+func JWTClaimsFrom(ctx context.Context) (map[string]string, bool) {
+	auth, ok := AuthorizerFrom(ctx)
+	if !ok || auth.JWT == nil {
+		return nil, false
+	}
+	return auth.JWT.Claims, true
+}
+
+func withStageVariables(ctx context.Context, vars map[string]string) context.Context {
+	return context.WithValue(ctx, stageVariablesKey, vars)
+}
+
+// StageVariablesFrom returns the stage variables of the Request from the context of a transformed http.Request. It
+// returns false if the stage has no stage variables.
+func StageVariablesFrom(ctx context.Context) (map[string]string, bool) {
+	vars, ok := ctx.Value(stageVariablesKey).(map[string]string)
+	return vars, ok && vars != nil
+}
@@ -0,0 +1,124 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContextFrom(t *testing.T) {
+	req := loadRequest(t, "httpapi-get.json")
+
+	var reqCtx RequestContext
+	var ok bool
+	_, err := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCtx, ok = RequestContextFrom(r.Context())
+	}))(context.Background(), *req)
+	if !assert.NoError(t, err, "failed to handle request") {
+		return
+	}
+
+	if !assert.True(t, ok, "missing request context") {
+		return
+	}
+	assert.Equal(t,
+		RequestContext{
+			AccountID:    "123456789012",
+			APIID:        "api-id",
+			DomainName:   "id.execute-api.us-east-1.amazonaws.com",
+			DomainPrefix: "id",
+			HTTP: RequestContextHTTP{
+				Method:    "GET",
+				Path:      "/my/path",
+				Protocol:  "HTTP/1.1",
+				SourceIP:  "192.0.2.1",
+				UserAgent: "agent",
+			},
+			RequestID: "id",
+			RouteKey:  "GET /my/{proxy+}",
+			Stage:     "$default",
+			Time:      "12/Mar/2020:19:03:58 +0000",
+			TimeEpoch: 1583348638390,
+		},
+		reqCtx)
+
+	tm, err := time.Parse(RequestTimeLayout, reqCtx.Time)
+	if assert.NoError(t, err, "failed to parse time") {
+		assert.Equal(t, time.Date(2020, 3, 12, 19, 3, 58, 0, time.UTC), tm.UTC())
+	}
+}
+
+func TestRequestContextFrom_Missing(t *testing.T) {
+	_, ok := RequestContextFrom(context.Background())
+	assert.False(t, ok)
+}
+
+func TestAuthorizerFrom(t *testing.T) {
+	newCtx := func(t *testing.T, authorizer string) context.Context {
+		var reqCtx RequestContext
+		if err := json.Unmarshal([]byte(`{"authorizer":`+authorizer+`}`), &reqCtx); err != nil {
+			t.Fatalf("failed to unmarshal request context: %v", err)
+		}
+		return withRequestContext(context.Background(), reqCtx)
+	}
+
+	t.Run("JWT", func(t *testing.T) {
+		ctx := newCtx(t, `{"jwt":{"claims":{"sub":"user1","groups":"[admin dev]"},"scopes":["read","write"]}}`)
+
+		claims, ok := JWTClaimsFrom(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, map[string]string{"sub": "user1", "groups": "[admin dev]"}, claims)
+
+		auth, ok := AuthorizerFrom(ctx)
+		if assert.True(t, ok) {
+			assert.Equal(t, []string{"read", "write"}, auth.JWT.Scopes)
+		}
+	})
+
+	t.Run("Lambda", func(t *testing.T) {
+		ctx := newCtx(t, `{"lambda":{"tenant":"tenant1","admin":true}}`)
+
+		auth, ok := AuthorizerFrom(ctx)
+		if assert.True(t, ok) {
+			assert.Equal(t, map[string]interface{}{"tenant": "tenant1", "admin": true}, auth.Lambda)
+		}
+
+		_, ok = JWTClaimsFrom(ctx)
+		assert.False(t, ok)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		ctx := newCtx(t, `null`)
+
+		_, ok := AuthorizerFrom(ctx)
+		assert.False(t, ok)
+		_, ok = JWTClaimsFrom(ctx)
+		assert.False(t, ok)
+	})
+}
+
+func TestStageVariablesFrom(t *testing.T) {
+	req := loadRequest(t, "httpapi-get.json")
+
+	httpReq, err := TransformRequest(context.Background(), req)
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	vars, ok := StageVariablesFrom(httpReq.Context())
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"stageVariable1": "value1"}, vars)
+
+	req.StageVariables = nil
+	httpReq, err = TransformRequest(context.Background(), req)
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	_, ok = StageVariablesFrom(httpReq.Context())
+	assert.False(t, ok)
+}
@@ -0,0 +1,16 @@
+package httpadapter
+
+import "harrisonhjones.com/go-apigw-http-adapter/internal/response"
+
+// EncodingPolicy decides whether TransformResponse base64 encodes the body of a HTTP API response.
+type EncodingPolicy = response.EncodingPolicy
+
+// BinaryMediaTypes returns an EncodingPolicy which encodes responses whose Content-Type matches one of mediaTypes,
+// e.g. "image/png" or "image/*".
+func BinaryMediaTypes(mediaTypes ...string) EncodingPolicy {
+	return response.BinaryMediaTypes(mediaTypes...)
+}
+
+// DefaultEncodingPolicy is used by Handler unless WithEncoding is given. It encodes every response which is not text,
+// e.g. an image or a gzip-encoded body, which HTTP APIs decode before sending it to the client.
+var DefaultEncodingPolicy EncodingPolicy = response.DefaultEncodingPolicy
@@ -0,0 +1,17 @@
+package httpadapter
+
+import "strings"
+
+// functionURLDomainInfix is present in every Lambda Function URL domain name: "<url-id>.lambda-url.<region>.on.aws".
+const functionURLDomainInfix = ".lambda-url."
+
+// IsFunctionURL reports whether the Request was sent by a Lambda Function URL rather than an HTTP API.
+//
+// Function URL events use the version 2.0 payload format and are transformed like any other Request. They differ from
+// HTTP API events in that RequestContext.RouteKey is always "$default", as Function URLs have no routes or stages, and
+// RequestContext.Authorizer.IAM is set when the Function URL uses the AWS_IAM auth type.
+// https://docs.aws.amazon.com/lambda/latest/dg/urls-invocation.html#urls-payloads
+func (r *Request) IsFunctionURL() bool {
+	return strings.Contains(r.RequestContext.DomainName, functionURLDomainInfix) &&
+		strings.HasSuffix(r.RequestContext.DomainName, ".on.aws")
+}
@@ -0,0 +1,62 @@
+package httpadapter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"harrisonhjones.com/go-apigw-http-adapter/lambdaruntime"
+)
+
+// HandlerFunc is a Lambda handler for Requests. It can be passed directly to lambda.Start or lambdaruntime.Start.
+type HandlerFunc func(context.Context, Request) (*Response, error)
+
+var _ lambdaruntime.Handler = HandlerFunc(nil)
+
+// Invoke implements lambdaruntime.Handler by decoding the payload into a Request and encoding the returned Response.
+func (f HandlerFunc) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	var req Request
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %v", err)
+	}
+
+	res, err := f(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response: %v", err)
+	}
+
+	return b, nil
+}
+
+// Handler returns a Lambda handler which transforms the Request, serves it using h, and transforms the result into a
+// Response.
+func Handler(h http.Handler, opts ...Option) HandlerFunc {
+	o := newOptions(opts)
+	return func(ctx context.Context, req Request) (*Response, error) {
+		httpReq, err := transformRequest(ctx, &req, &o.requestOptions)
+		if err != nil {
+			return o.errorHandler(ctx, err)
+		}
+
+		w := NewResponseWriter(o.maxBodySize)
+		h.ServeHTTP(w, httpReq)
+
+		httpRes := w.Result()
+		if err := w.Err(); err != nil {
+			return o.errorHandler(ctx, fmt.Errorf("failed to write response body: %w", err))
+		}
+
+		res, err := transformResponse(httpRes, o.encRes, &o.responseOptions)
+		if err != nil {
+			return o.errorHandler(ctx, err)
+		}
+
+		return res, nil
+	}
+}
@@ -0,0 +1,213 @@
+package httpadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_HappyPath(t *testing.T) {
+	req := Request{
+		Version:        "2.0",
+		RawQueryString: "name=World",
+		Cookies:        []string{"cookie1=val1"},
+		Headers: map[string]string{
+			"Header1": "value1",
+		},
+		RequestContext: RequestContext{
+			DomainName: "example.com",
+			HTTP: RequestContextHTTP{
+				Method: "POST",
+				Path:   "/my/path",
+			},
+		},
+		Body: "Hello Body!",
+	}
+
+	tstCtx := context.Background()
+
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCtx, ok := RequestContextFrom(r.Context())
+		assert.True(t, ok, "missing request context")
+		assert.Equal(t, req.RequestContext, reqCtx)
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/my/path", r.URL.Path)
+		assert.Equal(t, "value1", r.Header.Get("Header1"))
+
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err, "failed to read body")
+		assert.Equal(t, "Hello Body!", string(b))
+
+		http.SetCookie(w, &http.Cookie{Name: "cookie2", Value: "val2"})
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(201)
+		_, _ = fmt.Fprintf(w, "Hello %s!", r.URL.Query().Get("name"))
+	})
+
+	t.Run("NotEncoded", func(t *testing.T) {
+		res, err := Handler(mux)(tstCtx, req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t,
+			&Response{
+				StatusCode: 201,
+				Headers: map[string]string{
+					"Content-Type": "text/plain",
+				},
+				Body:    "Hello World!",
+				Cookies: []string{"cookie2=val2"},
+			},
+			res)
+	})
+
+	t.Run("Encoded", func(t *testing.T) {
+		h := Handler(mux, WithEncoding(func(*http.Response) bool { return true }))
+
+		res, err := h(tstCtx, req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, "SGVsbG8gV29ybGQh", res.Body) // FYI: base64.StdEncoding.EncodeToString([]byte("Hello World!"))
+		assert.True(t, res.IsBase64Encoded)
+	})
+}
+
+func TestHandler_DefaultEncodingPolicy(t *testing.T) {
+	req := Request{Version: "2.0", RequestContext: RequestContext{HTTP: RequestContextHTTP{Method: "GET", Path: "/"}}}
+
+	for ct, encoded := range map[string]bool{
+		"":                         false,
+		"application/json":         false,
+		"text/html; charset=utf-8": false,
+		"image/png":                true,
+	} {
+		res, err := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ct == "" {
+				w.Header()["Content-Type"] = nil // FYI: A nil value prevents Content-Type sniffing.
+			} else {
+				w.Header().Set("Content-Type", ct)
+			}
+			_, _ = w.Write([]byte{0, 1, 2, 3})
+		}))(context.Background(), req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, encoded, res.IsBase64Encoded, ct)
+	}
+}
+
+func TestHandler_TransformRequestError(t *testing.T) {
+	req := Request{Version: "blarg"}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		_, err := Handler(h)(context.Background(), req)
+
+		assert.EqualError(t, err, "unsupported version \"blarg\"")
+	})
+
+	t.Run("WithErrorHandler", func(t *testing.T) {
+		res, err := Handler(h, WithErrorHandler(func(ctx context.Context, err error) (*Response, error) {
+			return &Response{StatusCode: 400, Body: err.Error()}, nil
+		}))(context.Background(), req)
+
+		if !assert.NoError(t, err, "error handler error should be returned") {
+			return
+		}
+		assert.Equal(t, &Response{StatusCode: 400, Body: "unsupported version \"blarg\""}, res)
+	})
+}
+
+func TestHandler_RequestOption(t *testing.T) {
+	req := Request{
+		Version: "2.0",
+		RequestContext: RequestContext{
+			DomainName: "example.com",
+			HTTP: RequestContextHTTP{
+				Method: "GET",
+				Path:   "/",
+			},
+		},
+	}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, r.Host)
+	})
+
+	res, err := Handler(h, WithHost("localhost"), WithMaxBodySize(100))(context.Background(), req)
+	if !assert.NoError(t, err, "failed to handle request") {
+		return
+	}
+
+	assert.Equal(t, "localhost", res.Body)
+}
+
+func TestHandlerFunc_Invoke(t *testing.T) {
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s %s", r.Method, r.URL.Path)
+	}))
+
+	t.Run("HappyPath", func(t *testing.T) {
+		res, err := h.Invoke(context.Background(), []byte(`{
+			"version": "2.0",
+			"requestContext": {"domainName": "example.com", "http": {"method": "GET", "path": "/my/path"}}
+		}`))
+		if !assert.NoError(t, err, "failed to invoke handler") {
+			return
+		}
+
+		assert.JSONEq(t,
+			`{
+				"statusCode": 200,
+				"headers": {"Content-Type": "text/plain; charset=utf-8"},
+				"body": "GET /my/path",
+				"cookies": null
+			}`,
+			string(res))
+	})
+
+	t.Run("BadPayload", func(t *testing.T) {
+		_, err := h.Invoke(context.Background(), []byte(`blarg`))
+
+		assert.EqualError(t, err, "failed to unmarshal request: invalid character 'b' looking for beginning of value")
+	})
+}
+
+func TestHandler_MaxBodySize(t *testing.T) {
+	req := Request{Version: "2.0", RequestContext: RequestContext{DomainName: "example.com", HTTP: RequestContextHTTP{Method: "GET", Path: "/"}}}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte("Hello World!"))
+		assert.Equal(t, ErrBodyTooLarge, err)
+	})
+
+	_, err := Handler(h, WithMaxBodySize(5))(context.Background(), req)
+
+	assert.True(t, errors.Is(err, ErrBodyTooLarge), "error should wrap ErrBodyTooLarge")
+	assert.EqualError(t, err, "failed to write response body: response body exceeds the maximum size")
+}
+
+func TestHandler_ResponseOption(t *testing.T) {
+	req := Request{Version: "2.0", RequestContext: RequestContext{DomainName: "example.com", HTTP: RequestContextHTTP{Method: "GET", Path: "/"}}}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte{'a', 0xff})
+	})
+
+	_, err := Handler(h, WithUTF8Mode(UTF8Strict))(context.Background(), req)
+
+	assert.EqualError(t, err, "response body is not valid UTF-8 at byte 1")
+}
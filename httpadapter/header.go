@@ -0,0 +1,86 @@
+package httpadapter
+
+import (
+	"net/textproto"
+	"strings"
+)
+
+// HeaderSplitter splits the value of the request header name, e.g. "accept", into its values. v2 payloads join
+// repeated headers with commas, so TransformRequest has to decide which commas separate values and which are part of a
+// value, e.g. the commas of a Date or User-Agent header. name is as sent by API Gateway, which lowercases it.
+type HeaderSplitter func(name, value string) []string
+
+// ListHeaders returns a HeaderSplitter which splits the values of the given headers using SplitList and leaves every
+// other header, as well as list headers without any elements, as a single value. Header names are case-insensitive.
+func ListHeaders(names ...string) HeaderSplitter {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[textproto.CanonicalMIMEHeaderKey(name)] = true
+	}
+	return func(name, value string) []string {
+		if set[textproto.CanonicalMIMEHeaderKey(name)] {
+			if parts := SplitList(value); len(parts) > 0 {
+				return parts
+			}
+		}
+		return []string{value}
+	}
+}
+
+// DefaultHeaderSplitter splits the request headers which are defined as comma-separated lists by RFC 9110 and related
+// RFCs, e.g. Accept, Cache-Control and If-None-Match, as well as X-Forwarded-For. Every other header is a single value.
+var DefaultHeaderSplitter = ListHeaders(
+	"Accept",
+	"Accept-Charset",
+	"Accept-Encoding",
+	"Accept-Language",
+	"Access-Control-Request-Headers",
+	"Cache-Control",
+	"Connection",
+	"Content-Encoding",
+	"Content-Language",
+	"Expect",
+	"Forwarded",
+	"If-Match",
+	"If-None-Match",
+	"Pragma",
+	"Prefer",
+	"TE",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+	"Via",
+	"X-Forwarded-For",
+)
+
+// SplitList splits value, a comma-separated list as defined by RFC 9110 section 5.6.1, into its elements. Commas
+// within quoted strings, e.g. the entity tags of an If-None-Match header, do not separate elements. Whitespace around
+// elements is trimmed and empty elements are dropped.
+func SplitList(value string) []string {
+	var (
+		parts   []string
+		start   int
+		quoted  bool
+		escaped bool
+	)
+	add := func(part string) {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case escaped:
+			escaped = false
+		case quoted && c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case !quoted && c == ',':
+			add(value[start:i])
+			start = i + 1
+		}
+	}
+	add(value[start:])
+	return parts
+}
@@ -0,0 +1,87 @@
+package httpadapter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitList(t *testing.T) {
+	for value, want := range map[string][]string{
+		"gzip":                                 {"gzip"},
+		"gzip, deflate,br":                     {"gzip", "deflate", "br"},
+		"text/html;q=0.9, */*;q=0.8":           {"text/html;q=0.9", "*/*;q=0.8"},
+		`W/"a,b", "c"`:                         {`W/"a,b"`, `"c"`},
+		`text/plain; x="a\",b", text/html`:     {`text/plain; x="a\",b"`, "text/html"},
+		" , no-cache,, ":                       {"no-cache"},
+		"":                                     nil,
+		"198.51.100.1, 203.0.113.1":            {"198.51.100.1", "203.0.113.1"},
+		`for="[2001:db8::1]:80";proto=https,x`: {`for="[2001:db8::1]:80";proto=https`, "x"},
+	} {
+		assert.Equal(t, want, SplitList(value), value)
+	}
+}
+
+func TestDefaultHeaderSplitter(t *testing.T) {
+	for name, want := range map[string][]string{
+		"accept":            {"text/html", "application/json"},
+		"If-None-Match":     {`"a,b"`, `"c"`},
+		"date":              {"Tue, 15 Nov 1994 08:12:31 GMT"},
+		"if-modified-since": {"Tue, 15 Nov 1994 08:12:31 GMT"},
+		"user-agent":        {"Mozilla/5.0 (KHTML, like Gecko)"},
+		"x-json":            {`{"a":1,"b":2}`},
+	} {
+		value := strings.Join(want, ",")
+		assert.Equal(t, want, DefaultHeaderSplitter(name, value), name)
+	}
+
+	assert.Equal(t, []string{""}, DefaultHeaderSplitter("accept", ""))
+}
+
+func TestTransformRequest_HeaderSplitter(t *testing.T) {
+	req := Request{
+		Version: "2.0",
+		Headers: map[string]string{
+			"accept": "text/html,application/json",
+			"x-ids":  "1,2",
+		},
+		RequestContext: RequestContext{
+			DomainName: "example.com",
+			HTTP: RequestContextHTTP{
+				Method: "GET",
+				Path:   "/",
+			},
+		},
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, []string{"text/html", "application/json"}, httpReq.Header["Accept"])
+		assert.Equal(t, []string{"1,2"}, httpReq.Header["X-Ids"])
+	})
+
+	t.Run("Custom", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req, WithHeaderSplitter(ListHeaders("X-Ids")))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, []string{"text/html,application/json"}, httpReq.Header["Accept"])
+		assert.Equal(t, []string{"1", "2"}, httpReq.Header["X-Ids"])
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req, WithHeaderSplitter(nil))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, []string{"text/html", "application/json"}, httpReq.Header["Accept"])
+	})
+}
@@ -0,0 +1,171 @@
+package httpadapter
+
+import "context"
+
+// Option configures the behaviour of Handler. Every RequestOption and ResponseOption is also an Option.
+type Option interface {
+	apply(*options)
+}
+
+// optionFunc is an Option which only applies to Handler.
+type optionFunc func(*options)
+
+func (f optionFunc) apply(o *options) {
+	f(o)
+}
+
+// RequestOption configures the behaviour of TransformRequest. RequestOptions can also be passed to Handler.
+type RequestOption func(*requestOptions)
+
+func (f RequestOption) apply(o *options) {
+	f(&o.requestOptions)
+}
+
+// ResponseOption configures the behaviour of TransformResponse. ResponseOptions can also be passed to Handler.
+type ResponseOption func(*responseOptions)
+
+func (f ResponseOption) apply(o *options) {
+	f(&o.responseOptions)
+}
+
+type options struct {
+	requestOptions
+	responseOptions
+	errorHandler func(context.Context, error) (*Response, error)
+	encRes       EncodingPolicy
+	maxBodySize  int
+}
+
+type requestOptions struct {
+	// trustedProxies is the number of X-Forwarded-For entries to skip, or -1 to ignore X-Forwarded-For.
+	trustedProxies         int
+	scheme                 string
+	host                   string
+	clientCertErrorHandler func(error) error
+	headerSplitter         HeaderSplitter
+}
+
+type responseOptions struct {
+	utf8Mode UTF8Mode
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		requestOptions: defaultRequestOptions(),
+		errorHandler: func(_ context.Context, err error) (*Response, error) {
+			return nil, err
+		},
+		encRes:      DefaultEncodingPolicy,
+		maxBodySize: DefaultMaxBodySize,
+	}
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+	return o
+}
+
+func newRequestOptions(opts []RequestOption) *requestOptions {
+	o := defaultRequestOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &o
+}
+
+func newResponseOptions(opts []ResponseOption) *responseOptions {
+	o := &responseOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+func defaultRequestOptions() requestOptions {
+	return requestOptions{
+		trustedProxies: -1,
+		clientCertErrorHandler: func(err error) error {
+			return err
+		},
+		headerSplitter: DefaultHeaderSplitter,
+	}
+}
+
+// WithErrorHandler configures how request and response transformation errors are handled.
+// The returned *Response and error are returned from the Lambda handler as-is.
+// By default the transformation error is returned which results in a Lambda invocation error.
+func WithErrorHandler(fn func(ctx context.Context, err error) (*Response, error)) Option {
+	return optionFunc(func(o *options) {
+		o.errorHandler = fn
+	})
+}
+
+// WithEncoding configures which responses are base64 encoded. See TransformResponse for details.
+// Defaults to DefaultEncodingPolicy.
+func WithEncoding(encRes EncodingPolicy) Option {
+	return optionFunc(func(o *options) {
+		o.encRes = encRes
+	})
+}
+
+// WithMaxBodySize configures the maximum response body size. A response body which exceeds it is handled as an error
+// wrapping ErrBodyTooLarge. A maxBodySize <= 0 means no limit. Defaults to DefaultMaxBodySize.
+func WithMaxBodySize(maxBodySize int) Option {
+	return optionFunc(func(o *options) {
+		o.maxBodySize = maxBodySize
+	})
+}
+
+// WithUTF8Mode configures how response bodies which are not valid UTF-8 are handled. See UTF8Mode for details.
+// Defaults to UTF8Auto.
+func WithUTF8Mode(mode UTF8Mode) ResponseOption {
+	return func(o *responseOptions) {
+		o.utf8Mode = mode
+	}
+}
+
+// WithForwardedFor configures TransformRequest to take the http.Request RemoteAddr from the X-Forwarded-For header
+// rather than the source IP of the request. trustedProxies is the number of proxies in front of API Gateway, e.g. 1 for
+// a CloudFront distribution, whose entries are skipped. Only use this if API Gateway cannot be reached directly, as
+// clients can forge the header otherwise. By default the source IP is used.
+func WithForwardedFor(trustedProxies int) RequestOption {
+	return func(o *requestOptions) {
+		o.trustedProxies = trustedProxies
+	}
+}
+
+// WithScheme configures TransformRequest to use scheme, e.g. "https", for every request rather than the
+// X-Forwarded-Proto header.
+func WithScheme(scheme string) RequestOption {
+	return func(o *requestOptions) {
+		o.scheme = scheme
+	}
+}
+
+// WithHost configures TransformRequest to use host, e.g. "www.example.com", for every request rather than the Host
+// header. This is useful if API Gateway is behind a proxy which does not forward the Host header.
+func WithHost(host string) RequestOption {
+	return func(o *requestOptions) {
+		o.host = host
+	}
+}
+
+// WithClientCertErrorHandler configures how TransformRequest handles mutual TLS client certificates which cannot be
+// parsed. fn is called with an error wrapping ErrInvalidClientCert. If fn returns nil the request is transformed
+// without PeerCertificates, otherwise TransformRequest fails with the returned error. By default the error is returned
+// as-is, which results in the error handler being called.
+func WithClientCertErrorHandler(fn func(err error) error) RequestOption {
+	return func(o *requestOptions) {
+		o.clientCertErrorHandler = fn
+	}
+}
+
+// WithHeaderSplitter configures how TransformRequest splits request headers which API Gateway has joined with commas.
+// See HeaderSplitter for details. A nil fn restores the default, DefaultHeaderSplitter.
+func WithHeaderSplitter(fn HeaderSplitter) RequestOption {
+	return func(o *requestOptions) {
+		if fn == nil {
+			fn = DefaultHeaderSplitter
+		}
+		o.headerSplitter = fn
+	}
+}
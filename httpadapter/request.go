@@ -1,43 +1,145 @@
 package httpadapter
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
-	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"unicode/utf8"
+
+	"harrisonhjones.com/go-apigw-http-adapter/internal/request"
 )
 
+// ErrInvalidClientCert is wrapped by the error returned by TransformRequest if the mutual TLS client certificate of
+// the request cannot be parsed. See WithClientCertErrorHandler.
+var ErrInvalidClientCert = request.ErrInvalidClientCert
+
 // Request contains all relevant API Gateway HTTP API request data needed to transform it into a http.Request.
 type Request struct {
-	Version         string            `json:"version"`
-	RawQueryString  string            `json:"rawQueryString"`
-	Cookies         []string          `json:"cookies,omitempty"`
-	Headers         map[string]string `json:"headers"`
-	RequestContext  RequestContext    `json:"requestContext"`
-	Body            string            `json:"body,omitempty"`
-	IsBase64Encoded bool              `json:"isBase64Encoded"`
+	Version               string            `json:"version"`
+	RouteKey              string            `json:"routeKey,omitempty"`
+	RawPath               string            `json:"rawPath,omitempty"`
+	RawQueryString        string            `json:"rawQueryString"`
+	Cookies               []string          `json:"cookies,omitempty"`
+	Headers               map[string]string `json:"headers"`
+	QueryStringParameters map[string]string `json:"queryStringParameters,omitempty"`
+	PathParameters        map[string]string `json:"pathParameters,omitempty"`
+	StageVariables        map[string]string `json:"stageVariables,omitempty"`
+	RequestContext        RequestContext    `json:"requestContext"`
+	Body                  string            `json:"body,omitempty"`
+	IsBase64Encoded       bool              `json:"isBase64Encoded"`
 }
 
-// RequestContext contains all relevant data needed for Request transformation.
+// RequestContext contains the API Gateway metadata of the Request. It is stored in the context of the transformed
+// http.Request and can be retrieved using RequestContextFrom.
+// https://docs.aws.amazon.com/apigateway/latest/developerguide/http-api-develop-integrations-lambda.html
 type RequestContext struct {
-	DomainName string             `json:"domainName"`
-	HTTP       RequestContextHTTP `json:"http"`
+	AccountID      string                        `json:"accountId,omitempty"`
+	APIID          string                        `json:"apiId,omitempty"`
+	Authentication *RequestContextAuthentication `json:"authentication,omitempty"`
+	Authorizer     *RequestContextAuthorizer     `json:"authorizer,omitempty"`
+	DomainName     string                        `json:"domainName"`
+	DomainPrefix   string                        `json:"domainPrefix,omitempty"`
+	HTTP           RequestContextHTTP            `json:"http"`
+	RequestID      string                        `json:"requestId,omitempty"`
+	RouteKey       string                        `json:"routeKey,omitempty"`
+	Stage          string                        `json:"stage,omitempty"`
+	// Time is the request time in the "02/Jan/2006:15:04:05 -0700" format.
+	Time string `json:"time,omitempty"`
+	// TimeEpoch is the request time in milliseconds since the Unix epoch.
+	TimeEpoch int64 `json:"timeEpoch,omitempty"`
+}
+
+// RequestContextAuthentication contains the mutual TLS authentication data of the request, if any.
+type RequestContextAuthentication struct {
+	ClientCert *ClientCert `json:"clientCert,omitempty"`
+}
+
+// ClientCert contains the client certificate presented by the caller when mutual TLS is enabled. TransformRequest
+// parses ClientCertPEM into the PeerCertificates of the http.Request TLS state.
+type ClientCert struct {
+	ClientCertPEM string             `json:"clientCertPem"`
+	SubjectDN     string             `json:"subjectDN"`
+	IssuerDN      string             `json:"issuerDN"`
+	SerialNumber  string             `json:"serialNumber"`
+	Validity      ClientCertValidity `json:"validity"`
+}
+
+// ClientCertValidity contains the validity period of a ClientCert, e.g. "May 28 12:30:02 2019 GMT".
+type ClientCertValidity struct {
+	NotBefore string `json:"notBefore"`
+	NotAfter  string `json:"notAfter"`
+}
+
+// RequestTimeLayout is the layout of RequestContext.Time for use with time.Parse.
+const RequestTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// RequestContextAuthorizer contains the authorizer data for authenticated requests. Only the field matching the
+// authorizer type of the route is set. It can be retrieved using AuthorizerFrom.
+type RequestContextAuthorizer struct {
+	JWT *JWTAuthorizer `json:"jwt,omitempty"`
+	IAM *IAMAuthorizer `json:"iam,omitempty"`
+	// Lambda is the context returned by a Lambda authorizer.
+	Lambda map[string]interface{} `json:"lambda,omitempty"`
+}
+
+// JWTAuthorizer contains the claims and scopes of a request authorized by a JWT authorizer.
+// API Gateway converts every claim to a string, e.g. array claims are formatted as "[a b]".
+type JWTAuthorizer struct {
+	Claims map[string]string `json:"claims"`
+	Scopes []string          `json:"scopes"`
+}
+
+// IAMAuthorizer contains the identity of the caller of a request authenticated using AWS_IAM.
+type IAMAuthorizer struct {
+	AccessKey       string           `json:"accessKey"`
+	AccountID       string           `json:"accountId"`
+	CallerID        string           `json:"callerId"`
+	CognitoIdentity *CognitoIdentity `json:"cognitoIdentity,omitempty"`
+	PrincipalOrgID  string           `json:"principalOrgId,omitempty"`
+	UserARN         string           `json:"userArn"`
+	UserID          string           `json:"userId"`
+}
+
+// CognitoIdentity contains the Amazon Cognito identity of the caller, if any.
+type CognitoIdentity struct {
+	AMR            []string `json:"amr"`
+	IdentityID     string   `json:"identityId"`
+	IdentityPoolID string   `json:"identityPoolId"`
 }
 
-// RequestContextHTTP contains all relevant data needed for Request transformation.
+// RequestContextHTTP contains the HTTP details of the Request.
 type RequestContextHTTP struct {
-	Method string `json:"method"`
-	Path   string `json:"path"`
+	Method    string `json:"method"`
+	Path      string `json:"path"`
+	Protocol  string `json:"protocol,omitempty"`
+	SourceIP  string `json:"sourceIp,omitempty"`
+	UserAgent string `json:"userAgent,omitempty"`
 }
 
 // TransformRequest transforms a *Request to a *http.Request.
 // A non-nil error will be returned if the *Request is nil or if the transformation fails.
 // The *Request will not be mutated during transformation.
-func TransformRequest(ctx context.Context, req *Request) (*http.Request, error) {
+//
+// The URL path is taken from RawPath, if it matches RequestContext.HTTP.Path, so URL.EscapedPath is exactly what the
+// client sent. The URL scheme and Host are taken from the X-Forwarded-Proto, Host and X-Forwarded-Port headers unless
+// WithScheme or WithHost are passed. Proto is taken from the request context and TLS is set for https requests,
+// including the PeerCertificates of mutual TLS requests. RemoteAddr is set to the source IP of the request with a
+// placeholder port of 0, or taken from X-Forwarded-For if WithForwardedFor is passed. Headers joined with commas by API
+// Gateway are split using DefaultHeaderSplitter unless WithHeaderSplitter is passed. PathParameters are set as path
+// values, see http.Request.PathValue.
+//
+// The RequestContext and stage variables are stored in the context of the *http.Request, see RequestContextFrom and
+// StageVariablesFrom.
+func TransformRequest(ctx context.Context, req *Request, opts ...RequestOption) (*http.Request, error) {
+	return transformRequest(ctx, req, newRequestOptions(opts))
+}
+
+func transformRequest(ctx context.Context, req *Request, o *requestOptions) (*http.Request, error) {
 	if req == nil {
 		return nil, fmt.Errorf("req cannot be nil")
 	}
@@ -46,28 +148,19 @@ func TransformRequest(ctx context.Context, req *Request) (*http.Request, error)
 		return nil, fmt.Errorf("unsupported version %q", req.Version)
 	}
 
-	// Mirror how http.Request bodies normally behave.
-	// From the docs:
-	// For server requests, the Request Body is always non-nil
-	// but will return EOF immediately when no body is present.
-	var body io.Reader
-	if req.IsBase64Encoded {
-		b, err := base64.StdEncoding.DecodeString(req.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode body: %v", err)
-		}
-		body = bytes.NewBuffer(b)
-	} else {
-		body = strings.NewReader(req.Body)
+	body, err := request.Body(req.Body, req.IsBase64Encoded)
+	if err != nil {
+		return nil, err
 	}
 
-	rawUrl := "https://" + req.RequestContext.DomainName + req.RequestContext.HTTP.Path
-	if req.RawQueryString != "" {
-		rawUrl = rawUrl + "?" + req.RawQueryString
-	}
-	u, err := url.Parse(rawUrl)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse url: %v", err)
+	// FYI: RawPath preserves the encoding sent by the client, e.g. "%2F", which is lost in the decoded HTTP.Path.
+	path, rawPath := request.URLPath(req.RequestContext.HTTP.Path, req.RawPath)
+	u := &url.URL{
+		Scheme:   "https",
+		Host:     req.RequestContext.DomainName,
+		Path:     path,
+		RawPath:  rawPath,
+		RawQuery: req.RawQueryString,
 	}
 
 	hReq, err := http.NewRequest(req.RequestContext.HTTP.Method, u.String(), body)
@@ -75,18 +168,141 @@ func TransformRequest(ctx context.Context, req *Request) (*http.Request, error)
 		return nil, fmt.Errorf("failed to create new http request: %v", err)
 	}
 
+	ctx = withRequestContext(ctx, req.RequestContext)
+	ctx = withStageVariables(ctx, req.StageVariables)
 	hReq = hReq.WithContext(ctx)
 
-	for k, v := range req.Headers {
-		parts := strings.Split(v, ",")
-		for _, part := range parts {
-			hReq.Header.Add(k, part)
-		}
-	}
+	request.AddHeaders(hReq.Header, req.Headers, o.headerSplitter)
 
 	if len(req.Cookies) > 0 {
 		hReq.Header.Set("Cookie", strings.Join(req.Cookies, "; "))
 	}
 
+	request.SetServerFields(hReq, req.RequestContext.DomainName, req.RequestContext.HTTP.Protocol, o.scheme, o.host)
+	if auth := req.RequestContext.Authentication; auth != nil && auth.ClientCert != nil {
+		if err := request.SetPeerCertificates(hReq, auth.ClientCert.ClientCertPEM); err != nil {
+			if err := o.clientCertErrorHandler(err); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	request.SetPathValues(hReq, req.PathParameters)
+
+	hReq.RemoteAddr = request.RemoteAddr(req.RequestContext.HTTP.SourceIP)
+	if o.trustedProxies >= 0 {
+		if ip, ok := request.ForwardedFor(hReq.Header, o.trustedProxies); ok {
+			hReq.RemoteAddr = request.RemoteAddr(ip)
+		}
+	}
+
 	return hReq, nil
 }
+
+// NewRequestFromHTTP builds the *Request API Gateway would send for the *http.Request, e.g. for tests or to replay
+// requests. It is the inverse of TransformRequest. The *http.Request body is read and closed.
+//
+// Header names are lowercased and repeated headers are joined with commas, except for cookies which are split into
+// Cookies. Bodies which are not valid UTF-8 are base64 encoded. The route key and stage are always "$default". The
+// source IP is taken from r.RemoteAddr and X-Forwarded-Proto is added if missing. Fields which cannot be derived from
+// r, e.g. the request id and time, are left empty.
+func NewRequestFromHTTP(r *http.Request) (*Request, error) {
+	if r == nil {
+		return nil, fmt.Errorf("r cannot be nil")
+	}
+
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+
+	req := &Request{
+		Version:        "2.0",
+		RouteKey:       "$default",
+		RawPath:        r.URL.EscapedPath(),
+		RawQueryString: r.URL.RawQuery,
+		Headers:        map[string]string{},
+		RequestContext: RequestContext{
+			DomainName:   host,
+			DomainPrefix: domainPrefix(host),
+			HTTP: RequestContextHTTP{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Protocol:  r.Proto,
+				SourceIP:  sourceIP(r.RemoteAddr),
+				UserAgent: r.UserAgent(),
+			},
+			RouteKey: "$default",
+			Stage:    "$default",
+		},
+	}
+
+	for k, v := range r.Header {
+		if http.CanonicalHeaderKey(k) == "Cookie" {
+			for _, line := range v {
+				for _, ck := range strings.Split(line, ";") {
+					if ck = strings.TrimSpace(ck); ck != "" {
+						req.Cookies = append(req.Cookies, ck)
+					}
+				}
+			}
+			continue
+		}
+		req.Headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+	if host != "" {
+		req.Headers["host"] = host
+	}
+	if _, ok := req.Headers["x-forwarded-proto"]; !ok {
+		req.Headers["x-forwarded-proto"] = scheme(r)
+	}
+
+	query := r.URL.Query()
+	if len(query) > 0 {
+		req.QueryStringParameters = map[string]string{}
+		for k, v := range query {
+			req.QueryStringParameters[k] = strings.Join(v, ",")
+		}
+	}
+
+	if r.Body != nil {
+		defer r.Body.Close()
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body: %v", err)
+		}
+		if utf8.Valid(body) {
+			req.Body = string(body)
+		} else {
+			req.Body = base64.StdEncoding.EncodeToString(body)
+			req.IsBase64Encoded = true
+		}
+	}
+
+	return req, nil
+}
+
+// scheme returns the scheme r was received with.
+func scheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// domainPrefix returns the first label of host, e.g. "id" for "id.execute-api.us-east-1.amazonaws.com".
+func domainPrefix(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	prefix, _, _ := strings.Cut(host, ".")
+	return prefix
+}
+
+// sourceIP returns the IP of remoteAddr, which may or may not include a port.
+func sourceIP(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
@@ -1,9 +1,14 @@
 package httpadapter
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -20,7 +25,8 @@ func TestTransformRequest_HappyPath(t *testing.T) {
 		},
 		Headers: map[string]string{
 			"Header1":      "value1",
-			"Header2":      "value1,value2",
+			"Header2":      "value1,value2", // Not a list header so it is not split.
+			"accept":       "text/html, application/json;q=0.9",
 			"header-three": "value1,value2", // Non-canonical key.
 			"Header-three": "value3",        // Non-canonical key.
 			"Header-Three": "value4",        // Canonical key.
@@ -46,14 +52,17 @@ func TestTransformRequest_HappyPath(t *testing.T) {
 			return
 		}
 
-		assert.Equal(t, tstCtx, httpReq.Context())
+		reqCtx, ok := RequestContextFrom(httpReq.Context())
+		assert.True(t, ok, "missing request context")
+		assert.Equal(t, req.RequestContext, reqCtx)
 
 		assert.Equal(t,
 			http.Header{
+				"Accept":       []string{"text/html", "application/json;q=0.9"},
 				"Cookie":       []string{"cookie1=val1; cookie2=val2"},
-				"Header-Three": []string{"value1", "value2", "value3", "value4"},
+				"Header-Three": []string{"value4", "value3", "value1,value2"}, // FYI: Sorted by key.
 				"Header1":      []string{"value1"},
-				"Header2":      []string{"value1", "value2"},
+				"Header2":      []string{"value1,value2"},
 			},
 			httpReq.Header)
 
@@ -118,3 +127,270 @@ func TestTransformRequest_HappyPath(t *testing.T) {
 		assert.EqualError(t, err, "unsupported version \"blarg\"")
 	})
 }
+
+func TestNewRequestFromHTTP(t *testing.T) {
+	t.Run("Text", func(t *testing.T) {
+		httpReq, err := http.NewRequest("POST", "https://example.com/my/path%2Fencoded?parameter1=value1&parameter1=value2&parameter2=value", strings.NewReader("Hello World!"))
+		if !assert.NoError(t, err, "failed to create request") {
+			return
+		}
+		httpReq.Header.Add("Header1", "value1")
+		httpReq.Header.Add("Header2", "value1")
+		httpReq.Header.Add("Header2", "value2")
+		httpReq.Header.Add("Cookie", "cookie1=val1; cookie2=val2")
+		httpReq.Header.Add("Cookie", "cookie3=val3")
+
+		req, err := NewRequestFromHTTP(httpReq)
+		if !assert.NoError(t, err, "failed to create request") {
+			return
+		}
+
+		assert.Equal(t,
+			&Request{
+				Version:        "2.0",
+				RouteKey:       "$default",
+				RawPath:        "/my/path%2Fencoded",
+				RawQueryString: "parameter1=value1&parameter1=value2&parameter2=value",
+				Cookies:        []string{"cookie1=val1", "cookie2=val2", "cookie3=val3"},
+				Headers: map[string]string{
+					"header1":           "value1",
+					"header2":           "value1,value2",
+					"host":              "example.com",
+					"x-forwarded-proto": "http", // FYI: httpReq was not received over TLS.
+				},
+				QueryStringParameters: map[string]string{
+					"parameter1": "value1,value2",
+					"parameter2": "value",
+				},
+				RequestContext: RequestContext{
+					DomainName:   "example.com",
+					DomainPrefix: "example",
+					HTTP: RequestContextHTTP{
+						Method:   "POST",
+						Path:     "/my/path/encoded",
+						Protocol: "HTTP/1.1",
+					},
+					RouteKey: "$default",
+					Stage:    "$default",
+				},
+				Body:            "Hello World!",
+				IsBase64Encoded: false,
+			},
+			req)
+
+		// FYI: Round trip the request.
+		rtReq, err := TransformRequest(context.Background(), req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+		assert.Equal(t, "POST", rtReq.Method)
+		assert.Equal(t, "value1", rtReq.Header.Get("Header1"))
+		assert.Equal(t, []string{"value1", "value2"}, rtReq.URL.Query()["parameter1"])
+		assert.Len(t, rtReq.Cookies(), 3)
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		httpReq := httptest.NewRequest("PUT", "/upload", bytes.NewReader([]byte{0xff, 0x00, 0x01}))
+
+		req, err := NewRequestFromHTTP(httpReq)
+		if !assert.NoError(t, err, "failed to create request") {
+			return
+		}
+
+		assert.Equal(t, "example.com", req.RequestContext.DomainName)  // FYI: httptest.NewRequest uses example.com.
+		assert.Equal(t, "192.0.2.1", req.RequestContext.HTTP.SourceIP) // FYI: httptest.NewRequest uses 192.0.2.1:1234.
+		assert.Equal(t, "/wAB", req.Body)
+		assert.True(t, req.IsBase64Encoded)
+		assert.Nil(t, req.QueryStringParameters)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		_, err := NewRequestFromHTTP(nil)
+
+		assert.EqualError(t, err, "r cannot be nil")
+	})
+}
+
+func TestTransformRequest_RemoteAddr(t *testing.T) {
+	req := Request{
+		Version: "2.0",
+		Headers: map[string]string{
+			"x-forwarded-for": "198.51.100.1, 203.0.113.1",
+		},
+		RequestContext: RequestContext{
+			DomainName: "example.com",
+			HTTP: RequestContextHTTP{
+				Method:   "GET",
+				Path:     "/",
+				SourceIP: "203.0.113.1",
+			},
+		},
+	}
+
+	t.Run("SourceIP", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "203.0.113.1:0", httpReq.RemoteAddr)
+	})
+
+	t.Run("ForwardedFor", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req, WithForwardedFor(1))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "198.51.100.1:0", httpReq.RemoteAddr)
+	})
+
+	t.Run("NoSourceIP", func(t *testing.T) {
+		req := req
+		req.RequestContext.HTTP.SourceIP = ""
+
+		httpReq, err := TransformRequest(context.Background(), &req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Empty(t, httpReq.RemoteAddr)
+	})
+}
+
+func TestTransformRequest_ServerFields(t *testing.T) {
+	req := Request{
+		Version:        "2.0",
+		RawQueryString: "a=b",
+		Headers: map[string]string{
+			"host":              "api.example.com",
+			"x-forwarded-port":  "443",
+			"x-forwarded-proto": "https",
+		},
+		RequestContext: RequestContext{
+			DomainName: "id.execute-api.us-east-1.amazonaws.com",
+			HTTP: RequestContextHTTP{
+				Method:   "GET",
+				Path:     "/my/path",
+				Protocol: "HTTP/1.1",
+			},
+		},
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "https://api.example.com/my/path?a=b", httpReq.URL.String())
+		assert.Equal(t, "api.example.com", httpReq.Host)
+		assert.Equal(t, "HTTP/1.1", httpReq.Proto)
+		assert.Equal(t, "/my/path?a=b", httpReq.RequestURI)
+		if assert.NotNil(t, httpReq.TLS) {
+			assert.Equal(t, "api.example.com", httpReq.TLS.ServerName)
+		}
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req, WithScheme("http"), WithHost("localhost:8080"))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "http://localhost:8080/my/path?a=b", httpReq.URL.String())
+		assert.Equal(t, "localhost:8080", httpReq.Host)
+		assert.Nil(t, httpReq.TLS)
+	})
+}
+
+func TestTransformRequest_ClientCert(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/client.pem")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	newReq := func(pem string) *Request {
+		return &Request{
+			Version: "2.0",
+			RequestContext: RequestContext{
+				Authentication: &RequestContextAuthentication{
+					ClientCert: &ClientCert{ClientCertPEM: pem},
+				},
+				DomainName: "example.com",
+				HTTP:       RequestContextHTTP{Method: "GET", Path: "/"},
+			},
+		}
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), newReq(string(b)))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		if assert.NotNil(t, httpReq.TLS) && assert.Len(t, httpReq.TLS.PeerCertificates, 1) {
+			assert.Equal(t, "client.example.com", httpReq.TLS.PeerCertificates[0].Subject.CommonName)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := TransformRequest(context.Background(), newReq("CERT_CONTENT"))
+
+		assert.True(t, errors.Is(err, ErrInvalidClientCert))
+	})
+
+	t.Run("InvalidHandled", func(t *testing.T) {
+		_, err := TransformRequest(context.Background(), newReq("CERT_CONTENT"), WithClientCertErrorHandler(func(err error) error {
+			return fmt.Errorf("forbidden: %w", err)
+		}))
+
+		assert.EqualError(t, err, "forbidden: invalid client certificate: no PEM encoded certificate found")
+	})
+}
+
+func TestTransformRequest_RawPath(t *testing.T) {
+	// FYI: The encodings themselves are tested by internal/request.URLPath.
+	raw, decoded := "/files/a%2Fb.txt", "/files/a/b.txt"
+
+	t.Run("Encoded", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &Request{
+			Version: "2.0",
+			RawPath: raw,
+			RequestContext: RequestContext{
+				DomainName: "example.com",
+				HTTP:       RequestContextHTTP{Method: "GET", Path: decoded},
+			},
+		})
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, decoded, httpReq.URL.Path)
+		assert.Equal(t, raw, httpReq.URL.EscapedPath())
+		assert.Equal(t, raw, httpReq.RequestURI)
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		req, err := NewRequestFromHTTP(httptest.NewRequest("GET", raw, nil))
+		if !assert.NoError(t, err, "failed to create request") {
+			return
+		}
+
+		httpReq, err := TransformRequest(context.Background(), req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, raw, httpReq.URL.EscapedPath())
+	})
+}
+
+func TestTransformRequest_PathParameters(t *testing.T) {
+	httpReq, err := TransformRequest(context.Background(), loadRequest(t, "httpapi-get.json"))
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	assert.Equal(t, "path", httpReq.PathValue("proxy"))
+}
@@ -1,11 +1,13 @@
 package httpadapter
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -118,6 +120,21 @@ func TestTransformResponse_HappyPathEncoded(t *testing.T) {
 		response)
 }
 
+func TestTransformResponse_EmptyHeader(t *testing.T) {
+	res := &http.Response{
+		StatusCode: 200,
+		Header:     http.Header{"Content-Type": nil, "Key1": {"val1"}},
+		Body:       ioutil.NopCloser(strings.NewReader("Hello World!")),
+	}
+
+	response, err := TransformResponse(res, DefaultEncodingPolicy)
+	if !assert.NoError(t, err, "failed to transform response") {
+		return
+	}
+
+	assert.Equal(t, map[string]string{"Key1": "val1"}, response.Headers)
+}
+
 func TestTransformResponse_BadBody(t *testing.T) {
 	_, err := TransformResponse(&http.Response{
 		Body: ioutil.NopCloser(&FailingReader{}),
@@ -125,6 +142,34 @@ func TestTransformResponse_BadBody(t *testing.T) {
 	assert.EqualError(t, err, "failed to read response body: boom")
 }
 
+func TestTransformResponse_InvalidUTF8(t *testing.T) {
+	newRes := func() *http.Response {
+		recorder := httptest.NewRecorder()
+		recorder.Header().Set("Content-Type", "text/plain")
+		_, _ = recorder.Write([]byte{'a', 0xff, 'b'})
+		return recorder.Result()
+	}
+
+	t.Run("Auto", func(t *testing.T) {
+		res, err := TransformResponse(newRes(), nil)
+		if !assert.NoError(t, err, "failed to transform response") {
+			return
+		}
+
+		assert.Equal(t, "Yf9i", res.Body) // FYI: base64.StdEncoding.EncodeToString([]byte{'a', 0xff, 'b'})
+		assert.True(t, res.IsBase64Encoded)
+	})
+
+	t.Run("Strict", func(t *testing.T) {
+		_, err := TransformResponse(newRes(), nil, WithUTF8Mode(UTF8Strict))
+
+		var utf8Err *InvalidUTF8Error
+		if assert.True(t, errors.As(err, &utf8Err), "expected an *InvalidUTF8Error") {
+			assert.Equal(t, 1, utf8Err.Offset)
+		}
+	})
+}
+
 type FailingReader struct{}
 
 func (f FailingReader) Read([]byte) (n int, err error) {
@@ -132,3 +177,91 @@ func (f FailingReader) Read([]byte) (n int, err error) {
 }
 
 var _ io.Reader = &FailingReader{}
+
+func TestResponse_ToHTTP(t *testing.T) {
+	t.Run("NotEncoded", func(t *testing.T) {
+		res := &Response{
+			StatusCode: 201,
+			Headers: map[string]string{
+				"key1": "val1",
+				"Key2": "val2a,val2b",
+			},
+			Body: "Hello World!",
+			Cookies: []string{
+				"cookie1-name=cookie1-value",
+				"cookie2-name=cookie2-value",
+			},
+		}
+
+		httpRes, err := res.ToHTTP()
+		if !assert.NoError(t, err, "failed to transform response") {
+			return
+		}
+
+		assert.Equal(t, 201, httpRes.StatusCode)
+		assert.Equal(t, "201 Created", httpRes.Status)
+		assert.Equal(t,
+			http.Header{
+				"Key1":       {"val1"},
+				"Key2":       {"val2a,val2b"},
+				"Set-Cookie": {"cookie1-name=cookie1-value", "cookie2-name=cookie2-value"},
+			},
+			httpRes.Header)
+		assert.Len(t, httpRes.Cookies(), 2)
+
+		b, err := ioutil.ReadAll(httpRes.Body)
+		if !assert.NoError(t, err, "failed to read body") {
+			return
+		}
+		assert.Equal(t, []byte("Hello World!"), b)
+		assert.Equal(t, int64(12), httpRes.ContentLength)
+	})
+
+	t.Run("Encoded", func(t *testing.T) {
+		res := &Response{
+			StatusCode:      200,
+			Body:            "SGVsbG8gRW5jb2RlZCBXb3JsZCE=", // FYI: base64.StdEncoding.EncodeToString([]byte("Hello Encoded World!"))
+			IsBase64Encoded: true,
+		}
+
+		httpRes, err := res.ToHTTP()
+		if !assert.NoError(t, err, "failed to transform response") {
+			return
+		}
+
+		b, err := ioutil.ReadAll(httpRes.Body)
+		if !assert.NoError(t, err, "failed to read body") {
+			return
+		}
+		assert.Equal(t, []byte("Hello Encoded World!"), b)
+	})
+
+	t.Run("IncorrectlyEncoded", func(t *testing.T) {
+		_, err := (&Response{Body: "blarg", IsBase64Encoded: true}).ToHTTP()
+
+		assert.EqualError(t, err, "failed to decode body: illegal base64 data at input byte 4")
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		http.SetCookie(recorder, &http.Cookie{Name: "cookie1-name", Value: "cookie1-value"})
+		recorder.Header().Set("key1", "val1")
+		recorder.WriteHeader(202)
+		_, _ = recorder.WriteString("Hello World!")
+
+		res, err := TransformResponse(recorder.Result(), func(*http.Response) bool { return true })
+		if !assert.NoError(t, err, "failed to transform response") {
+			return
+		}
+
+		httpRes, err := res.ToHTTP()
+		if !assert.NoError(t, err, "failed to transform response") {
+			return
+		}
+
+		assert.Equal(t, 202, httpRes.StatusCode)
+		assert.Equal(t, recorder.Result().Header, httpRes.Header)
+		b, _ := ioutil.ReadAll(httpRes.Body)
+		assert.Equal(t, []byte("Hello World!"), b)
+	})
+}
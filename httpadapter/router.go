@@ -0,0 +1,66 @@
+package httpadapter
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Router is an http.Handler which dispatches transformed requests on the route key of the API Gateway route which
+// matched them, e.g. "GET /items/{id}", rather than matching the path again. Requests to routes without a handler,
+// e.g. "$default" or "ANY /{proxy+}", and requests without a RequestContext are passed to the fallback handler.
+//
+// Path parameters of the route are available via http.Request.PathValue.
+type Router struct {
+	mu       sync.RWMutex
+	routes   map[string]http.Handler
+	fallback http.Handler
+}
+
+// NewRouter returns a Router which passes requests to unknown routes to fallback. A nil fallback responds with
+// 404 Not Found.
+func NewRouter(fallback http.Handler) *Router {
+	if fallback == nil {
+		fallback = http.NotFoundHandler()
+	}
+	return &Router{
+		routes:   map[string]http.Handler{},
+		fallback: fallback,
+	}
+}
+
+// Handle registers h for the route with the given route key, exactly as configured in API Gateway, e.g.
+// "GET /items/{id}" or "ANY /items". Handle panics if a handler is already registered for routeKey.
+func (rt *Router) Handle(routeKey string, h http.Handler) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	if _, ok := rt.routes[routeKey]; ok {
+		panic("httpadapter: multiple registrations for route " + routeKey)
+	}
+	rt.routes[routeKey] = h
+}
+
+// HandleFunc registers fn for the route with the given route key. See Handle for details.
+func (rt *Router) HandleFunc(routeKey string, fn func(http.ResponseWriter, *http.Request)) {
+	rt.Handle(routeKey, http.HandlerFunc(fn))
+}
+
+// ServeHTTP implements http.Handler.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.handler(r).ServeHTTP(w, r)
+}
+
+func (rt *Router) handler(r *http.Request) http.Handler {
+	reqCtx, ok := RequestContextFrom(r.Context())
+	if !ok {
+		return rt.fallback
+	}
+
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	if h, ok := rt.routes[reqCtx.RouteKey]; ok {
+		return h
+	}
+	return rt.fallback
+}
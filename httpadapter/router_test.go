@@ -0,0 +1,71 @@
+package httpadapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	rt := NewRouter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "fallback")
+	}))
+	rt.HandleFunc("GET /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "item %s", r.PathValue("id"))
+	})
+	rt.HandleFunc("ANY /items", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "items %s", r.Method)
+	})
+
+	h := Handler(rt)
+	for name, tc := range map[string]struct {
+		routeKey   string
+		method     string
+		path       string
+		pathParams map[string]string
+		want       string
+	}{
+		"PathParameter": {"GET /items/{id}", "GET", "/items/1", map[string]string{"id": "1"}, "item 1"},
+		"AnyMethod":     {"ANY /items", "POST", "/items", nil, "items POST"},
+		"Default":       {"$default", "GET", "/other", nil, "fallback"},
+		"Proxy":         {"ANY /{proxy+}", "GET", "/other", map[string]string{"proxy": "other"}, "fallback"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := h(context.Background(), Request{
+				Version:        "2.0",
+				RouteKey:       tc.routeKey,
+				PathParameters: tc.pathParams,
+				RequestContext: RequestContext{
+					DomainName: "example.com",
+					HTTP:       RequestContextHTTP{Method: tc.method, Path: tc.path},
+					RouteKey:   tc.routeKey,
+				},
+			})
+			if !assert.NoError(t, err, "failed to handle request") {
+				return
+			}
+
+			assert.Equal(t, tc.want, res.Body)
+		})
+	}
+}
+
+func TestRouter_NoRequestContext(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewRouter(nil).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, 404, rec.Code)
+}
+
+func TestRouter_DuplicateRoute(t *testing.T) {
+	rt := NewRouter(nil)
+	rt.Handle("GET /items", http.NotFoundHandler())
+
+	assert.PanicsWithValue(t, "httpadapter: multiple registrations for route GET /items", func() {
+		rt.Handle("GET /items", http.NotFoundHandler())
+	})
+}
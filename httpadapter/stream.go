@@ -0,0 +1,262 @@
+package httpadapter
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"harrisonhjones.com/go-apigw-http-adapter/internal/response"
+	"harrisonhjones.com/go-apigw-http-adapter/lambdaruntime"
+)
+
+// StreamHandlerFunc is a streaming Lambda handler for Requests. It can be passed directly to lambdaruntime.StartStream.
+type StreamHandlerFunc func(ctx context.Context, req Request, w io.Writer) error
+
+var _ lambdaruntime.StreamHandler = StreamHandlerFunc(nil)
+
+// ContentType implements lambdaruntime.StreamHandler. Responses are streamed in the HTTP integration response format.
+func (f StreamHandlerFunc) ContentType() string {
+	return lambdaruntime.HTTPIntegrationResponseContentType
+}
+
+// InvokeStream implements lambdaruntime.StreamHandler by decoding the payload into a Request.
+func (f StreamHandlerFunc) InvokeStream(ctx context.Context, payload []byte, w io.Writer) error {
+	var req Request
+	if err := json.Unmarshal(payload, &req); err != nil {
+		return fmt.Errorf("failed to unmarshal request: %v", err)
+	}
+
+	return f(ctx, req, w)
+}
+
+// StreamingHandler returns a streaming Lambda handler which transforms the Request and serves it using h. The
+// handler's writes and flushes are streamed using a StreamingResponseWriter instead of being buffered.
+// Responses are never base64 encoded so WithEncoding and WithMaxBodySize have no effect.
+func StreamingHandler(h http.Handler, opts ...Option) StreamHandlerFunc {
+	o := newOptions(opts)
+	return func(ctx context.Context, req Request, w io.Writer) error {
+		httpReq, err := transformRequest(ctx, &req, &o.requestOptions)
+		if err != nil {
+			res, err := o.errorHandler(ctx, err)
+			if err != nil {
+				return err
+			}
+			return writeStreamingResponse(w, res)
+		}
+
+		sw := NewStreamingResponseWriter(w)
+		h.ServeHTTP(sw, httpReq)
+
+		return sw.Close()
+	}
+}
+
+// writeStreamingResponse streams an already transformed Response.
+func writeStreamingResponse(w io.Writer, res *Response) error {
+	body := []byte(res.Body)
+	if res.IsBase64Encoded {
+		b, err := base64.StdEncoding.DecodeString(res.Body)
+		if err != nil {
+			return fmt.Errorf("failed to decode body: %v", err)
+		}
+		body = b
+	}
+
+	sw := NewStreamingResponseWriter(w)
+	for k, v := range res.Headers {
+		sw.Header().Set(k, v)
+	}
+	for _, ck := range res.Cookies {
+		sw.Header().Add("Set-Cookie", ck)
+	}
+	sw.WriteHeader(res.StatusCode)
+	if _, err := sw.Write(body); err != nil {
+		return err
+	}
+
+	return sw.Close()
+}
+
+// streamingPrelude is the JSON document sent before the body of a streamed HTTP integration response.
+type streamingPrelude struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Cookies    []string          `json:"cookies,omitempty"`
+}
+
+// streamingPreludeDelimiter separates the prelude from the body.
+var streamingPreludeDelimiter = make([]byte, 8)
+
+// StreamingResponseWriter is an http.ResponseWriter which streams the response in the Lambda HTTP integration response
+// format: a JSON prelude containing the status code, headers and cookies, eight NUL bytes, and then the body.
+//
+// The prelude is written when the header is committed by the first non-empty Write or Flush. Body writes are passed
+// straight through to the underlying writer. Flush also flushes the underlying writer if it supports it.
+type StreamingResponseWriter struct {
+	mu          sync.Mutex
+	w           io.Writer
+	header      http.Header
+	snapHeader  http.Header
+	status      int
+	wroteHeader bool
+	committed   bool
+	closed      bool
+	err         error
+}
+
+var (
+	_ http.ResponseWriter = &StreamingResponseWriter{}
+	_ http.Flusher        = &StreamingResponseWriter{}
+	_ http.Hijacker       = &StreamingResponseWriter{}
+)
+
+// NewStreamingResponseWriter returns a StreamingResponseWriter which writes to w.
+func NewStreamingResponseWriter(w io.Writer) *StreamingResponseWriter {
+	return &StreamingResponseWriter{
+		w:      w,
+		header: http.Header{},
+	}
+}
+
+// Header returns the header map that will be sent in the prelude.
+func (w *StreamingResponseWriter) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the status code and a snapshot of the headers. Only the first call has any effect.
+// Informational (1xx) status codes cannot be sent by Lambda and are ignored.
+func (w *StreamingResponseWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.writeHeader(code)
+}
+
+func (w *StreamingResponseWriter) writeHeader(code int) {
+	if code < 100 || code > 999 {
+		panic(fmt.Sprintf("invalid WriteHeader code %v", code))
+	}
+	if w.wroteHeader || w.closed || code < 200 {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	w.snapHeader = w.header.Clone()
+}
+
+// Write streams b, writing the prelude first if it has not already been written.
+func (w *StreamingResponseWriter) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+
+	w.writeHeader(http.StatusOK)
+	// FYI: As with net/http, empty writes do not commit the prelude so the Content-Type is sniffed from the first
+	// non-empty write.
+	if len(b) == 0 {
+		return 0, w.err
+	}
+	if err := w.commit(b); err != nil {
+		return 0, err
+	}
+
+	n, err := w.w.Write(b)
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	return n, err
+}
+
+func (w *StreamingResponseWriter) commit(b []byte) error {
+	if w.committed {
+		return w.err
+	}
+	w.committed = true
+
+	response.SniffContentType(w.snapHeader, b)
+	response.DropEmpty(w.snapHeader)
+
+	prelude := streamingPrelude{
+		StatusCode: w.status,
+		Headers:    map[string]string{},
+	}
+	for k, v := range w.snapHeader {
+		if strings.ToLower(k) == "set-cookie" {
+			prelude.Cookies = append(prelude.Cookies, v...)
+			continue
+		}
+		prelude.Headers[k] = strings.Join(v, ",")
+	}
+
+	b, err := json.Marshal(prelude)
+	if err != nil {
+		w.err = fmt.Errorf("failed to marshal prelude: %v", err)
+		return w.err
+	}
+
+	if _, err := w.w.Write(append(b, streamingPreludeDelimiter...)); err != nil {
+		w.err = err
+	}
+	return w.err
+}
+
+// Flush implements http.Flusher.
+func (w *StreamingResponseWriter) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError commits the prelude and flushes the underlying writer if it implements http.Flusher or
+// FlushError() error. It is used by http.ResponseController.
+func (w *StreamingResponseWriter) FlushError() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+
+	w.writeHeader(http.StatusOK)
+	if err := w.commit(nil); err != nil {
+		return err
+	}
+
+	switch f := w.w.(type) {
+	case interface{ FlushError() error }:
+		return f.FlushError()
+	case http.Flusher:
+		f.Flush()
+	}
+	return nil
+}
+
+// Hijack implements http.Hijacker and always returns ErrHijackNotSupported.
+func (w *StreamingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, ErrHijackNotSupported
+}
+
+// Close finalizes the response, writing the prelude if nothing has been written yet. Subsequent writes return
+// ErrClosed. Close returns the first error encountered while writing to the underlying writer.
+func (w *StreamingResponseWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return w.err
+	}
+
+	w.writeHeader(http.StatusOK)
+	err := w.commit(nil)
+	w.closed = true
+
+	return err
+}
@@ -0,0 +1,210 @@
+package httpadapter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"harrisonhjones.com/go-apigw-http-adapter/lambdaruntime"
+)
+
+const streamingDelimiter = "\x00\x00\x00\x00\x00\x00\x00\x00"
+
+func TestStreamingResponseWriter_HappyPath(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStreamingResponseWriter(&buf)
+	http.SetCookie(w, &http.Cookie{Name: "cookie1-name", Value: "cookie1-value"})
+	http.SetCookie(w, &http.Cookie{Name: "cookie2-name", Value: "cookie2-value"})
+	w.Header().Add("key1", "val1")
+	w.Header().Add("key1", "val2")
+	w.WriteHeader(201)
+	_, err := w.Write([]byte("Hello "))
+	assert.NoError(t, err, "failed to write")
+	_, err = w.Write([]byte("World!"))
+	assert.NoError(t, err, "failed to write")
+	assert.NoError(t, w.Close(), "failed to close")
+
+	assert.Equal(t,
+		`{"statusCode":201,"headers":{"Content-Type":"text/plain; charset=utf-8","Key1":"val1,val2"},`+
+			`"cookies":["cookie1-name=cookie1-value","cookie2-name=cookie2-value"]}`+
+			streamingDelimiter+
+			"Hello World!",
+		buf.String())
+
+	_, err = w.Write([]byte("blarg"))
+	assert.Equal(t, ErrClosed, err)
+}
+
+func TestStreamingResponseWriter_NoBody(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStreamingResponseWriter(&buf)
+	w.WriteHeader(204)
+	assert.NoError(t, w.Close(), "failed to close")
+
+	assert.Equal(t, `{"statusCode":204}`+streamingDelimiter, buf.String())
+}
+
+func TestStreamingResponseWriter_EmptyWrite(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStreamingResponseWriter(&buf)
+	n, err := w.Write(nil)
+	assert.NoError(t, err, "failed to write")
+	assert.Equal(t, 0, n)
+	assert.Equal(t, "", buf.String())
+
+	_, _ = w.Write([]byte("<html></html>"))
+	assert.NoError(t, w.Close(), "failed to close")
+
+	assert.Equal(t,
+		`{"statusCode":200,"headers":{"Content-Type":"text/html; charset=utf-8"}}`+streamingDelimiter+"<html></html>",
+		buf.String())
+}
+
+func TestStreamingResponseWriter_SuppressedContentType(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStreamingResponseWriter(&buf)
+	w.Header()["Content-Type"] = nil
+	_, _ = w.Write([]byte("<html></html>"))
+	assert.NoError(t, w.Close(), "failed to close")
+
+	assert.Equal(t, `{"statusCode":200}`+streamingDelimiter+"<html></html>", buf.String())
+}
+
+type flushRecorder struct {
+	bytes.Buffer
+	flushes int
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushes++
+}
+
+func TestStreamingResponseWriter_Flush(t *testing.T) {
+	var buf flushRecorder
+	w := NewStreamingResponseWriter(&buf)
+	rc := http.NewResponseController(w)
+
+	assert.NoError(t, rc.Flush(), "failed to flush")
+	assert.Equal(t, `{"statusCode":200}`+streamingDelimiter, buf.String())
+	assert.Equal(t, 1, buf.flushes)
+
+	_, _, err := rc.Hijack()
+	assert.Equal(t, ErrHijackNotSupported, err)
+}
+
+func TestStreamingResponseWriter_WriteError(t *testing.T) {
+	w := NewStreamingResponseWriter(&FailingWriter{})
+
+	_, err := w.Write([]byte("Hello World!"))
+
+	assert.EqualError(t, err, "boom")
+	assert.EqualError(t, w.Close(), "boom")
+}
+
+type FailingWriter struct{}
+
+func (f FailingWriter) Write([]byte) (n int, err error) {
+	return 0, fmt.Errorf("boom")
+}
+
+func TestStreamingHandler_TransformRequestError(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		err := StreamingHandler(h)(context.Background(), Request{Version: "blarg"}, ioutil.Discard)
+
+		assert.EqualError(t, err, "unsupported version \"blarg\"")
+	})
+
+	t.Run("WithErrorHandler", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := StreamingHandler(h, WithErrorHandler(func(ctx context.Context, err error) (*Response, error) {
+			return &Response{
+				StatusCode:      400,
+				Headers:         map[string]string{"Content-Type": "text/plain"},
+				Body:            "YmFkIHJlcXVlc3Q=", // FYI: base64.StdEncoding.EncodeToString([]byte("bad request"))
+				IsBase64Encoded: true,
+			}, nil
+		}))(context.Background(), Request{Version: "blarg"}, &buf)
+
+		if !assert.NoError(t, err, "error handler error should be returned") {
+			return
+		}
+		assert.Equal(t, `{"statusCode":400,"headers":{"Content-Type":"text/plain"}}`+streamingDelimiter+"bad request", buf.String())
+	})
+}
+
+// TestStreamingHandler_Runtime streams a response through a local stand-in for the Lambda Runtime API and checks that
+// the first event is received before the handler writes the second.
+func TestStreamingHandler_Runtime(t *testing.T) {
+	firstEvent := "data: first\n\n"
+	received := make(chan struct{})
+	var streamed []byte
+
+	served := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/2018-06-01/runtime/invocation/next" && !served:
+			served = true
+			w.Header().Set("Lambda-Runtime-Aws-Request-Id", "request-1")
+			_, _ = io.WriteString(w, `{
+				"version": "2.0",
+				"requestContext": {"domainName": "example.com", "http": {"method": "GET", "path": "/events"}}
+			}`)
+		case r.URL.Path == "/2018-06-01/runtime/invocation/request-1/response":
+			assert.Equal(t, lambdaruntime.HTTPIntegrationResponseContentType, r.Header.Get("Content-Type"))
+			assert.Equal(t, "streaming", r.Header.Get("Lambda-Runtime-Function-Response-Mode"))
+
+			for !strings.Contains(string(streamed), firstEvent) {
+				b := make([]byte, 512)
+				n, err := r.Body.Read(b)
+				streamed = append(streamed, b[:n]...)
+				if err != nil {
+					break
+				}
+			}
+			close(received)
+
+			rest, _ := ioutil.ReadAll(r.Body)
+			streamed = append(streamed, rest...)
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer srv.Close()
+
+	h := StreamingHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, firstEvent)
+		w.(http.Flusher).Flush()
+
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Error("first event was not streamed before the handler returned")
+		}
+
+		_, _ = io.WriteString(w, "data: second\n\n")
+	}))
+
+	c := lambdaruntime.NewClient(strings.TrimPrefix(srv.URL, "http://"))
+	err := lambdaruntime.ServeStream(context.Background(), c, h)
+
+	assert.EqualError(t, err, "unexpected next invocation status 410: ")
+	assert.Equal(t,
+		`{"statusCode":200,"headers":{"Content-Type":"text/event-stream"}}`+streamingDelimiter+
+			"data: first\n\ndata: second\n\n",
+		string(streamed))
+}
@@ -0,0 +1,114 @@
+// Package mediatype matches Content-Type values against media type patterns the way API Gateway matches
+// binaryMediaTypes.
+package mediatype
+
+import (
+	"strings"
+)
+
+// Pattern is a media type which may contain wildcards, e.g. "image/png", "image/*" or "*/*", and parameters, e.g.
+// "text/plain; charset=utf-8".
+type Pattern struct {
+	typ     string
+	subtype string
+	params  map[string]string
+}
+
+// Parse parses a media type pattern. Type, subtype and parameter names are case-insensitive, as are parameter values.
+// A bare "*" is equivalent to "*/*".
+func Parse(s string) Pattern {
+	mt, params := split(s)
+	if mt == "*" {
+		mt = "*/*"
+	}
+	typ, subtype, _ := strings.Cut(mt, "/")
+	return Pattern{typ: typ, subtype: subtype, params: params}
+}
+
+// Match reports whether contentType matches the Pattern. Every parameter of the Pattern must be present in
+// contentType with the same value; additional parameters in contentType are ignored.
+func (p Pattern) Match(contentType string) bool {
+	mt, params := split(contentType)
+	typ, subtype, ok := strings.Cut(mt, "/")
+	if !ok || typ == "" || subtype == "" {
+		return false
+	}
+
+	if p.typ != "*" && p.typ != typ {
+		return false
+	}
+	if p.subtype != "*" && p.subtype != subtype {
+		return false
+	}
+
+	for k, v := range p.params {
+		if params[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Matcher matches a Content-Type against a list of Patterns.
+type Matcher []Pattern
+
+// NewMatcher returns a Matcher for the given patterns.
+func NewMatcher(patterns ...string) Matcher {
+	m := make(Matcher, 0, len(patterns))
+	for _, p := range patterns {
+		m = append(m, Parse(p))
+	}
+	return m
+}
+
+// Match reports whether contentType matches any of the Matcher's Patterns.
+func (m Matcher) Match(contentType string) bool {
+	for _, p := range m {
+		if p.Match(contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// textTypes are the media types which are sent as text by default.
+var textTypes = NewMatcher(
+	"text/*",
+	"application/json",
+	"application/xml",
+	"application/javascript",
+	"application/x-javascript",
+	"application/ecmascript",
+)
+
+// IsText reports whether contentType is a textual media type: text/*, JSON, XML or JavaScript, including structured
+// syntax suffixes such as "application/problem+json" or "image/svg+xml".
+func IsText(contentType string) bool {
+	if textTypes.Match(contentType) {
+		return true
+	}
+
+	mt, _ := split(contentType)
+	return strings.HasSuffix(mt, "+json") || strings.HasSuffix(mt, "+xml")
+}
+
+// split splits s into its lowercased media type and parameters. Parameters without a value are ignored.
+func split(s string) (string, map[string]string) {
+	parts := strings.Split(s, ";")
+	mt := strings.ToLower(strings.TrimSpace(parts[0]))
+
+	var params map[string]string
+	for _, part := range parts[1:] {
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		if params == nil {
+			params = map[string]string{}
+		}
+		k = strings.ToLower(strings.TrimSpace(k))
+		v = strings.ToLower(strings.Trim(strings.TrimSpace(v), `"`))
+		params[k] = v
+	}
+	return mt, params
+}
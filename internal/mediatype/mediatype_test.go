@@ -0,0 +1,65 @@
+package mediatype
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPattern_Match(t *testing.T) {
+	for _, tc := range []struct {
+		pattern     string
+		contentType string
+		want        bool
+	}{
+		{"image/png", "image/png", true},
+		{"image/png", "IMAGE/PNG", true},
+		{"image/png", "image/png; q=1", true},
+		{"image/png", "image/jpeg", false},
+		{"image/*", "image/jpeg", true},
+		{"image/*", "text/plain", false},
+		{"*/*", "application/octet-stream", true},
+		{"*", "application/octet-stream", true},
+		{"*/*", "", false},
+		{"*/*", "blarg", false},
+		{"text/plain; charset=utf-8", "text/plain; charset=UTF-8", true},
+		{"text/plain; charset=utf-8", `text/plain; charset="utf-8"; format=flowed`, true},
+		{"text/plain; charset=utf-8", "text/plain; charset=iso-8859-1", false},
+		{"text/plain; charset=utf-8", "text/plain", false},
+	} {
+		assert.Equal(t, tc.want, Parse(tc.pattern).Match(tc.contentType), "%q matching %q", tc.pattern, tc.contentType)
+	}
+}
+
+func TestMatcher_Match(t *testing.T) {
+	m := NewMatcher("image/*", "application/pdf")
+
+	assert.True(t, m.Match("image/gif"))
+	assert.True(t, m.Match("application/pdf"))
+	assert.False(t, m.Match("application/json"))
+	assert.False(t, NewMatcher().Match("application/pdf"))
+}
+
+func TestIsText(t *testing.T) {
+	for _, ct := range []string{
+		"text/plain; charset=utf-8",
+		"text/html",
+		"application/json",
+		"application/problem+json",
+		"application/xml",
+		"image/svg+xml",
+		"application/javascript",
+	} {
+		assert.True(t, IsText(ct), ct)
+	}
+
+	for _, ct := range []string{
+		"",
+		"application/octet-stream",
+		"application/pdf",
+		"application/gzip",
+		"image/png",
+	} {
+		assert.False(t, IsText(ct), ct)
+	}
+}
@@ -0,0 +1,23 @@
+package request
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Body returns a reader for the body of an event, decoding it if isBase64Encoded is set. As with http.Request bodies
+// on the server, the reader is never nil but returns EOF immediately when there is no body.
+func Body(body string, isBase64Encoded bool) (io.Reader, error) {
+	if !isBase64Encoded {
+		return strings.NewReader(body), nil
+	}
+
+	b, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode body: %v", err)
+	}
+	return bytes.NewReader(b), nil
+}
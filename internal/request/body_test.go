@@ -0,0 +1,44 @@
+package request
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBody(t *testing.T) {
+	read := func(t *testing.T, r io.Reader) string {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("failed to read body: %v", err)
+		}
+		return string(b)
+	}
+
+	t.Run("Plain", func(t *testing.T) {
+		r, err := Body("Hello World!", false)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Hello World!", read(t, r))
+		}
+	})
+
+	t.Run("Base64", func(t *testing.T) {
+		r, err := Body("SGVsbG8gV29ybGQh", true)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "Hello World!", read(t, r))
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		r, err := Body("", false)
+		if assert.NoError(t, err) && assert.NotNil(t, r) {
+			assert.Equal(t, "", read(t, r))
+		}
+	})
+
+	t.Run("InvalidBase64", func(t *testing.T) {
+		_, err := Body("!", true)
+		assert.EqualError(t, err, "failed to decode body: illegal base64 data at input byte 0")
+	})
+}
@@ -0,0 +1,52 @@
+package request
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ErrInvalidClientCert is wrapped by the error returned by SetPeerCertificates if the client certificate cannot be
+// parsed.
+var ErrInvalidClientCert = errors.New("invalid client certificate")
+
+// SetPeerCertificates parses the PEM encoded client certificate chain reported by API Gateway for mutual TLS requests
+// and sets r.TLS.PeerCertificates. r.TLS is created if needed. An empty clientCertPEM is ignored.
+//
+// API Gateway has already verified the chain against the truststore of the domain name so r.TLS.VerifiedChains is left
+// empty; the truststore itself is not available.
+func SetPeerCertificates(r *http.Request, clientCertPEM string) error {
+	if clientCertPEM == "" {
+		return nil
+	}
+
+	var certs []*x509.Certificate
+	rest := []byte(clientCertPEM)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidClientCert, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return fmt.Errorf("%w: no PEM encoded certificate found", ErrInvalidClientCert)
+	}
+
+	if r.TLS == nil {
+		r.TLS = &tls.ConnectionState{HandshakeComplete: true}
+	}
+	r.TLS.PeerCertificates = certs
+	return nil
+}
@@ -0,0 +1,56 @@
+package request
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPeerCertificates(t *testing.T) {
+	b, err := ioutil.ReadFile("testdata/client.pem")
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "https://example.com/", nil)
+
+		err := SetPeerCertificates(r, string(b))
+		if !assert.NoError(t, err, "failed to set peer certificates") {
+			return
+		}
+
+		if assert.NotNil(t, r.TLS) && assert.Len(t, r.TLS.PeerCertificates, 1) {
+			assert.Equal(t, "client.example.com", r.TLS.PeerCertificates[0].Subject.CommonName)
+		}
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "https://example.com/", nil)
+
+		assert.NoError(t, SetPeerCertificates(r, ""))
+		assert.Nil(t, r.TLS)
+	})
+
+	t.Run("NoPEM", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "https://example.com/", nil)
+
+		err := SetPeerCertificates(r, "CERT_CONTENT")
+
+		assert.True(t, errors.Is(err, ErrInvalidClientCert))
+		assert.EqualError(t, err, "invalid client certificate: no PEM encoded certificate found")
+		assert.Nil(t, r.TLS)
+	})
+
+	t.Run("InvalidDER", func(t *testing.T) {
+		r, _ := http.NewRequest("GET", "https://example.com/", nil)
+
+		err := SetPeerCertificates(r, strings.Replace(string(b), "MIIB", "AAAA", 1))
+
+		assert.True(t, errors.Is(err, ErrInvalidClientCert))
+	})
+}
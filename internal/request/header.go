@@ -0,0 +1,39 @@
+package request
+
+import (
+	"net/http"
+	"sort"
+)
+
+// AddHeaders adds headers to h. split splits each value into its values; a nil split adds every value as-is.
+func AddHeaders(h http.Header, headers map[string]string, split func(name, value string) []string) {
+	for _, k := range SortedKeys(headers) {
+		if split == nil {
+			h.Add(k, headers[k])
+			continue
+		}
+		for _, v := range split(k, headers[k]) {
+			h.Add(k, v)
+		}
+	}
+}
+
+// AddMultiValueHeaders adds headers to h.
+func AddMultiValueHeaders(h http.Header, headers map[string][]string) {
+	for _, k := range SortedKeys(headers) {
+		for _, v := range headers[k] {
+			h.Add(k, v)
+		}
+	}
+}
+
+// SortedKeys returns the keys of m in sorted order. It is used to add headers whose keys only differ in case, e.g.
+// "x-id" and "X-Id", in a deterministic order.
+func SortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
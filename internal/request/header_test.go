@@ -0,0 +1,36 @@
+package request
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedKeys(t *testing.T) {
+	assert.Equal(t, []string{"X-Id", "X-id", "x-id"}, SortedKeys(map[string]int{"x-id": 1, "X-Id": 2, "X-id": 3}))
+	assert.Empty(t, SortedKeys(map[string]int(nil)))
+}
+
+func TestAddHeaders(t *testing.T) {
+	t.Run("NilSplit", func(t *testing.T) {
+		h := http.Header{}
+		AddHeaders(h, map[string]string{"x-id": "1", "X-Id": "2", "accept": "a, b"}, nil)
+		assert.Equal(t, http.Header{"X-Id": {"2", "1"}, "Accept": {"a, b"}}, h)
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		h := http.Header{}
+		AddHeaders(h, map[string]string{"accept": "a,b"}, func(name, value string) []string {
+			return strings.Split(value, ",")
+		})
+		assert.Equal(t, http.Header{"Accept": {"a", "b"}}, h)
+	})
+}
+
+func TestAddMultiValueHeaders(t *testing.T) {
+	h := http.Header{}
+	AddMultiValueHeaders(h, map[string][]string{"x-id": {"1", "2"}, "X-Id": {"3"}})
+	assert.Equal(t, http.Header{"X-Id": {"3", "1", "2"}}, h)
+}
@@ -0,0 +1,39 @@
+package request
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// URLPath returns the Path and RawPath of a url.URL for a request path, so that the URL's EscapedPath is exactly what
+// the client sent. raw is the path as sent by the client, still percent-encoded, and decoded its decoded form; either
+// may be empty. raw is ignored if it is not a valid encoding of decoded.
+//
+// If only raw is available and it cannot be decoded, e.g. "/100%", it is used as the decoded path.
+func URLPath(decoded, raw string) (path, rawPath string) {
+	if raw == "" {
+		return decoded, ""
+	}
+
+	p, err := url.PathUnescape(raw)
+	switch {
+	case err != nil && decoded == "":
+		return raw, ""
+	case err != nil, decoded != "" && p != decoded:
+		return decoded, ""
+	}
+
+	// FYI: Mirror url.URL.setPath which only sets RawPath if it differs from the default encoding.
+	if (&url.URL{Path: p}).EscapedPath() == raw {
+		return p, ""
+	}
+	return p, raw
+}
+
+// SetPathValues sets the path parameters matched by API Gateway as path values of r, see http.Request.PathValue. A
+// http.ServeMux serving r replaces the values of the wildcards of its own pattern and keeps the rest.
+func SetPathValues(r *http.Request, params map[string]string) {
+	for k, v := range params {
+		r.SetPathValue(k, v)
+	}
+}
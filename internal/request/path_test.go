@@ -0,0 +1,62 @@
+package request
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURLPath(t *testing.T) {
+	for name, tc := range map[string]struct {
+		decoded, raw      string
+		wantPath, wantRaw string
+	}{
+		"Plain":           {"/my/path", "/my/path", "/my/path", ""},
+		"EncodedSlash":    {"/my/a/b", "/my/a%2Fb", "/my/a/b", "/my/a%2Fb"},
+		"RawOnly":         {"", "/my/a%2Fb", "/my/a/b", "/my/a%2Fb"},
+		"DecodedOnly":     {"/my/a b", "", "/my/a b", ""},
+		"DefaultEncoding": {"/my/a b", "/my/a%20b", "/my/a b", ""},
+		"Mismatch":        {"/prod/my/path", "/my/path", "/prod/my/path", ""},
+		"InvalidRaw":      {"/100%", "/100%", "/100%", ""},
+		"InvalidRawOnly":  {"", "/100%", "/100%", ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			path, rawPath := URLPath(tc.decoded, tc.raw)
+
+			assert.Equal(t, tc.wantPath, path)
+			assert.Equal(t, tc.wantRaw, rawPath)
+		})
+	}
+}
+
+func TestURLPath_EscapedPath(t *testing.T) {
+	for _, raw := range []string{
+		"/my/path", "/my/a%2Fb", "/s3/key%2Bwith%2Bplus", "/a%20b", "/a%3Fb%23c", "/%E4%B8%96", "/%E4%B8%96%2F%E7%95%8C",
+	} {
+		path, rawPath := URLPath("", raw)
+
+		assert.Equal(t, raw, (&url.URL{Path: path, RawPath: rawPath}).EscapedPath())
+	}
+}
+
+func TestSetPathValues(t *testing.T) {
+	r := httptest.NewRequest("GET", "/my/path", nil)
+	SetPathValues(r, map[string]string{"proxy": "my/path", "id": "api-gateway"})
+
+	assert.Equal(t, "my/path", r.PathValue("proxy"))
+
+	// FYI: A http.ServeMux replaces the values of its own wildcards and keeps the rest.
+	var id, proxy string
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /my/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id = r.PathValue("id")
+		proxy = r.PathValue("proxy")
+	})
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Equal(t, "path", id)
+	assert.Equal(t, "my/path", proxy)
+}
@@ -0,0 +1,48 @@
+// Package request implements the http.Request transformation helpers shared by the adapter packages.
+package request
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// placeholderPort is used as the port of RemoteAddr as API Gateway does not report the client port. It keeps
+// RemoteAddr parseable by net.SplitHostPort.
+const placeholderPort = "0"
+
+// RemoteAddr returns the http.Request RemoteAddr for sourceIP, e.g. "192.0.2.1:0" or "[2001:db8::1]:0". An empty
+// sourceIP results in an empty RemoteAddr.
+func RemoteAddr(sourceIP string) string {
+	if sourceIP == "" {
+		return ""
+	}
+	return net.JoinHostPort(sourceIP, placeholderPort)
+}
+
+// ForwardedFor returns the client IP from the X-Forwarded-For chain of h. The rightmost entry is the address API
+// Gateway received the request from, so with trustedProxies proxies in front of API Gateway, e.g. 1 for CloudFront,
+// the client is trustedProxies entries further left. The leftmost entry is returned if the chain is shorter than
+// that. It returns false if h has no X-Forwarded-For header or the selected entry is not an IP.
+func ForwardedFor(h http.Header, trustedProxies int) (string, bool) {
+	var chain []string
+	for _, v := range h.Values("X-Forwarded-For") {
+		for _, ip := range strings.Split(v, ",") {
+			if ip = strings.TrimSpace(ip); ip != "" {
+				chain = append(chain, ip)
+			}
+		}
+	}
+	if len(chain) == 0 {
+		return "", false
+	}
+
+	i := len(chain) - 1 - trustedProxies
+	if i < 0 {
+		i = 0
+	}
+	if net.ParseIP(chain[i]) == nil {
+		return "", false
+	}
+	return chain[i], true
+}
@@ -0,0 +1,42 @@
+package request
+
+import (
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteAddr(t *testing.T) {
+	assert.Equal(t, "192.0.2.1:0", RemoteAddr("192.0.2.1"))
+	assert.Equal(t, "[2001:db8::1]:0", RemoteAddr("2001:db8::1"))
+	assert.Equal(t, "", RemoteAddr(""))
+
+	host, port, err := net.SplitHostPort(RemoteAddr("2001:db8::1"))
+	if assert.NoError(t, err, "failed to split remote addr") {
+		assert.Equal(t, "2001:db8::1", host)
+		assert.Equal(t, "0", port)
+	}
+}
+
+func TestForwardedFor(t *testing.T) {
+	h := http.Header{"X-Forwarded-For": {"198.51.100.1, 203.0.113.1", "192.0.2.1"}}
+
+	for trustedProxies, want := range map[int]string{
+		0: "192.0.2.1",
+		1: "203.0.113.1",
+		2: "198.51.100.1",
+		5: "198.51.100.1",
+	} {
+		ip, ok := ForwardedFor(h, trustedProxies)
+		assert.True(t, ok)
+		assert.Equal(t, want, ip, "trustedProxies %d", trustedProxies)
+	}
+
+	_, ok := ForwardedFor(http.Header{}, 1)
+	assert.False(t, ok)
+
+	_, ok = ForwardedFor(http.Header{"X-Forwarded-For": {"unknown, 192.0.2.1"}}, 1)
+	assert.False(t, ok)
+}
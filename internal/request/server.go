@@ -0,0 +1,81 @@
+package request
+
+import (
+	"crypto/tls"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// defaultPorts are the ports which are omitted from the host for each scheme.
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+}
+
+// SetServerFields sets the URL scheme and host, Host, Proto, RequestURI and TLS fields of r the way net/http's server
+// does, as if r had been received directly rather than through API Gateway.
+//
+// The scheme is taken from the X-Forwarded-Proto header and defaults to https. The host is taken from the Host header,
+// which is removed from r.Header as with net/http, and defaults to domainName. The X-Forwarded-Port is added to the
+// host unless it is the default port of the scheme. Non-empty scheme and host arguments override the derived values.
+// protocol is the protocol reported by API Gateway, e.g. "HTTP/1.1", and is ignored if it cannot be parsed.
+//
+// r.TLS is set for https requests so code checking r.TLS != nil behaves as it would behind a TLS terminating proxy.
+// Only ServerName and HandshakeComplete are known.
+func SetServerFields(r *http.Request, domainName, protocol, scheme, host string) {
+	if scheme == "" {
+		scheme = forwardedProto(r.Header)
+	}
+
+	if host == "" {
+		host = r.Header.Get("Host")
+		if host == "" {
+			host = domainName
+		}
+		if port := r.Header.Get("X-Forwarded-Port"); port != "" && port != defaultPorts[scheme] && !hasPort(host) {
+			host = net.JoinHostPort(host, port)
+		}
+	}
+	r.Header.Del("Host")
+
+	r.URL.Scheme = scheme
+	r.URL.Host = host
+	r.Host = host
+
+	if major, minor, ok := http.ParseHTTPVersion(protocol); ok {
+		r.Proto = protocol
+		r.ProtoMajor = major
+		r.ProtoMinor = minor
+	}
+
+	r.RequestURI = r.URL.RequestURI()
+
+	r.TLS = nil
+	if scheme == "https" {
+		serverName := host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			serverName = h
+		}
+		r.TLS = &tls.ConnectionState{
+			HandshakeComplete: true,
+			ServerName:        serverName,
+		}
+	}
+}
+
+// forwardedProto returns the lowercased first X-Forwarded-Proto value of h, or https if there is none.
+func forwardedProto(h http.Header) string {
+	proto, _, _ := strings.Cut(h.Get("X-Forwarded-Proto"), ",")
+	proto = strings.ToLower(strings.TrimSpace(proto))
+	if proto == "" {
+		return "https"
+	}
+	return proto
+}
+
+// hasPort reports whether host includes a port.
+func hasPort(host string) bool {
+	_, _, err := net.SplitHostPort(host)
+	return err == nil
+}
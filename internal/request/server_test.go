@@ -0,0 +1,74 @@
+package request
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetServerFields(t *testing.T) {
+	newRequest := func(t *testing.T, header http.Header) *http.Request {
+		r, err := http.NewRequest("GET", "https://ignored.example.com/my/path?a=b", nil)
+		if err != nil {
+			t.Fatalf("failed to create request: %v", err)
+		}
+		r.Header = header
+		return r
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		r := newRequest(t, http.Header{})
+		SetServerFields(r, "id.execute-api.us-east-1.amazonaws.com", "HTTP/2.0", "", "")
+
+		assert.Equal(t, "https://id.execute-api.us-east-1.amazonaws.com/my/path?a=b", r.URL.String())
+		assert.Equal(t, "id.execute-api.us-east-1.amazonaws.com", r.Host)
+		assert.Equal(t, "HTTP/2.0", r.Proto)
+		assert.Equal(t, 2, r.ProtoMajor)
+		assert.Equal(t, 0, r.ProtoMinor)
+		assert.Equal(t, "/my/path?a=b", r.RequestURI)
+		if assert.NotNil(t, r.TLS) {
+			assert.Equal(t, "id.execute-api.us-east-1.amazonaws.com", r.TLS.ServerName)
+			assert.True(t, r.TLS.HandshakeComplete)
+		}
+	})
+
+	t.Run("Forwarded", func(t *testing.T) {
+		r := newRequest(t, http.Header{
+			"Host":              {"api.example.com"},
+			"X-Forwarded-Proto": {"HTTP"},
+			"X-Forwarded-Port":  {"8080"},
+		})
+		SetServerFields(r, "id.execute-api.us-east-1.amazonaws.com", "blarg", "", "")
+
+		assert.Equal(t, "http://api.example.com:8080/my/path?a=b", r.URL.String())
+		assert.Equal(t, "api.example.com:8080", r.Host)
+		assert.Empty(t, r.Header.Get("Host"))
+		assert.Equal(t, "HTTP/1.1", r.Proto) // FYI: Unparseable protocols are ignored.
+		assert.Nil(t, r.TLS)
+	})
+
+	t.Run("DefaultPort", func(t *testing.T) {
+		r := newRequest(t, http.Header{
+			"Host":             {"api.example.com"},
+			"X-Forwarded-Port": {"443"},
+		})
+		SetServerFields(r, "", "", "", "")
+
+		assert.Equal(t, "api.example.com", r.Host)
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		r := newRequest(t, http.Header{
+			"Host":              {"api.example.com"},
+			"X-Forwarded-Proto": {"http"},
+		})
+		SetServerFields(r, "", "", "https", "www.example.com:8443")
+
+		assert.Equal(t, "https://www.example.com:8443/my/path?a=b", r.URL.String())
+		assert.Equal(t, "www.example.com:8443", r.Host)
+		if assert.NotNil(t, r.TLS) {
+			assert.Equal(t, "www.example.com", r.TLS.ServerName)
+		}
+	})
+}
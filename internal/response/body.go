@@ -0,0 +1,59 @@
+package response
+
+import (
+	"encoding/base64"
+	"fmt"
+	"unicode/utf8"
+)
+
+// UTF8Mode configures how EncodeBody handles bodies which are not valid UTF-8 and would otherwise not be encoded.
+// Such bodies cannot be sent as-is as encoding/json replaces invalid UTF-8 with U+FFFD when marshalling the response.
+type UTF8Mode int
+
+const (
+	// UTF8Auto base64 encodes bodies which are not valid UTF-8.
+	UTF8Auto UTF8Mode = iota
+	// UTF8Strict rejects bodies which are not valid UTF-8 with an *InvalidUTF8Error.
+	UTF8Strict
+)
+
+// InvalidUTF8Error is returned by EncodeBody in UTF8Strict mode when a body which is not base64 encoded contains
+// invalid UTF-8. Such a body cannot be represented as a JSON string without replacing the invalid bytes.
+type InvalidUTF8Error struct {
+	// Offset is the index of the first invalid byte.
+	Offset int
+}
+
+func (e *InvalidUTF8Error) Error() string {
+	return fmt.Sprintf("response body is not valid UTF-8 at byte %d", e.Offset)
+}
+
+// EncodeBody returns body as a string, base64 encoded if encode is true. A body which is not valid UTF-8 is encoded
+// regardless of encode in UTF8Auto mode and rejected in UTF8Strict mode.
+func EncodeBody(body []byte, encode bool, mode UTF8Mode) (string, bool, error) {
+	if !encode {
+		off := invalidUTF8(body)
+		if off < 0 {
+			return string(body), false, nil
+		}
+		if mode == UTF8Strict {
+			return "", false, &InvalidUTF8Error{Offset: off}
+		}
+	}
+	return base64.StdEncoding.EncodeToString(body), true, nil
+}
+
+// invalidUTF8 returns the index of the first invalid UTF-8 byte in b or -1 if b is valid UTF-8.
+func invalidUTF8(b []byte) int {
+	if utf8.Valid(b) {
+		return -1
+	}
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
@@ -0,0 +1,49 @@
+package response
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeBody(t *testing.T) {
+	t.Run("Text", func(t *testing.T) {
+		for _, mode := range []UTF8Mode{UTF8Auto, UTF8Strict} {
+			s, encoded, err := EncodeBody([]byte("Hello 世界!"), false, mode)
+			if !assert.NoError(t, err, "failed to encode body") {
+				return
+			}
+			assert.Equal(t, "Hello 世界!", s)
+			assert.False(t, encoded)
+		}
+	})
+
+	t.Run("Encoded", func(t *testing.T) {
+		s, encoded, err := EncodeBody([]byte("Hello World!"), true, UTF8Strict)
+		if !assert.NoError(t, err, "failed to encode body") {
+			return
+		}
+		assert.Equal(t, "SGVsbG8gV29ybGQh", s) // FYI: base64.StdEncoding.EncodeToString([]byte("Hello World!"))
+		assert.True(t, encoded)
+	})
+
+	t.Run("InvalidUTF8Auto", func(t *testing.T) {
+		s, encoded, err := EncodeBody([]byte{'a', 0xff, 'b'}, false, UTF8Auto)
+		if !assert.NoError(t, err, "failed to encode body") {
+			return
+		}
+		assert.Equal(t, "Yf9i", s)
+		assert.True(t, encoded)
+	})
+
+	t.Run("InvalidUTF8Strict", func(t *testing.T) {
+		_, _, err := EncodeBody([]byte("世界\xff"), false, UTF8Strict)
+
+		var utf8Err *InvalidUTF8Error
+		if assert.True(t, errors.As(err, &utf8Err)) {
+			assert.Equal(t, 6, utf8Err.Offset)
+		}
+		assert.EqualError(t, err, "response body is not valid UTF-8 at byte 6")
+	})
+}
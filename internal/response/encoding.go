@@ -0,0 +1,31 @@
+package response
+
+import (
+	"net/http"
+
+	"harrisonhjones.com/go-apigw-http-adapter/internal/mediatype"
+)
+
+// EncodingPolicy decides whether the body of a response is base64 encoded.
+type EncodingPolicy func(*http.Response) bool
+
+// BinaryMediaTypes returns an EncodingPolicy which encodes responses whose Content-Type matches any of the given media
+// types, just like an API Gateway binaryMediaTypes list. Media types may be exact, e.g. "image/png", or use wildcards,
+// e.g. "image/*" or "*/*". Parameters, e.g. "text/plain; charset=iso-8859-1", must all be present in the Content-Type
+// for it to match.
+func BinaryMediaTypes(mediaTypes ...string) EncodingPolicy {
+	m := mediatype.NewMatcher(mediaTypes...)
+	return func(res *http.Response) bool {
+		return m.Match(res.Header.Get("Content-Type"))
+	}
+}
+
+// DefaultEncodingPolicy encodes every response whose Content-Type is not text/*, JSON, XML or JavaScript, as well as
+// every response with a Content-Encoding such as gzip. Responses without a Content-Type are not encoded.
+func DefaultEncodingPolicy(res *http.Response) bool {
+	if ce := res.Header.Get("Content-Encoding"); ce != "" && ce != "identity" {
+		return true
+	}
+	ct := res.Header.Get("Content-Type")
+	return ct != "" && !mediatype.IsText(ct)
+}
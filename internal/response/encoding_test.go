@@ -0,0 +1,44 @@
+package response
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBinaryMediaTypes(t *testing.T) {
+	p := BinaryMediaTypes("image/*", "application/pdf", "text/plain; charset=iso-8859-1")
+
+	for ct, want := range map[string]bool{
+		"image/png":                       true,
+		"application/pdf":                 true,
+		"text/plain; charset=ISO-8859-1":  true,
+		"text/plain; charset=utf-8":       false,
+		"application/json; charset=utf-8": false,
+		"":                                false,
+	} {
+		assert.Equal(t, want, p(&http.Response{Header: http.Header{"Content-Type": {ct}}}), ct)
+	}
+}
+
+func TestDefaultEncodingPolicy(t *testing.T) {
+	for ct, want := range map[string]bool{
+		"text/html; charset=utf-8": false,
+		"application/json":         false,
+		"application/hal+json":     false,
+		"application/xml":          false,
+		"application/javascript":   false,
+		"image/png":                true,
+		"application/pdf":          true,
+		"application/gzip":         true,
+		"":                         false,
+	} {
+		assert.Equal(t, want, DefaultEncodingPolicy(&http.Response{Header: http.Header{"Content-Type": {ct}}}), ct)
+	}
+
+	assert.True(t, DefaultEncodingPolicy(&http.Response{Header: http.Header{
+		"Content-Type":     {"application/json"},
+		"Content-Encoding": {"gzip"},
+	}}))
+}
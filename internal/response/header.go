@@ -0,0 +1,24 @@
+package response
+
+import "net/http"
+
+// SniffContentType sets the Content-Type of h from b, the first non-empty write of the body, as net/http does. Nothing
+// is set if b is empty, a Content-Type key, even a nil one, is already present, or h has a Transfer-Encoding.
+func SniffContentType(h http.Header, b []byte) {
+	if len(b) == 0 || h.Get("Transfer-Encoding") != "" {
+		return
+	}
+	if _, ok := h["Content-Type"]; !ok {
+		h.Set("Content-Type", http.DetectContentType(b))
+	}
+}
+
+// DropEmpty deletes the headers of h without values, e.g. a nil Content-Type used to prevent sniffing, as net/http
+// does not send them.
+func DropEmpty(h http.Header) {
+	for k, v := range h {
+		if len(v) == 0 {
+			delete(h, k)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniffContentType(t *testing.T) {
+	t.Run("Detected", func(t *testing.T) {
+		h := http.Header{}
+		SniffContentType(h, []byte("<html></html>"))
+		assert.Equal(t, http.Header{"Content-Type": {"text/html; charset=utf-8"}}, h)
+	})
+
+	t.Run("EmptyBody", func(t *testing.T) {
+		h := http.Header{}
+		SniffContentType(h, nil)
+		assert.Equal(t, http.Header{}, h)
+	})
+
+	t.Run("Suppressed", func(t *testing.T) {
+		h := http.Header{"Content-Type": nil}
+		SniffContentType(h, []byte("<html></html>"))
+		assert.Equal(t, http.Header{"Content-Type": nil}, h)
+	})
+
+	t.Run("TransferEncoding", func(t *testing.T) {
+		h := http.Header{"Transfer-Encoding": {"chunked"}}
+		SniffContentType(h, []byte("<html></html>"))
+		assert.Equal(t, http.Header{"Transfer-Encoding": {"chunked"}}, h)
+	})
+}
+
+func TestDropEmpty(t *testing.T) {
+	h := http.Header{"Content-Type": nil, "Key1": {"val1"}, "Key2": {}}
+	DropEmpty(h)
+	assert.Equal(t, http.Header{"Key1": {"val1"}}, h)
+}
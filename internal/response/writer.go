@@ -0,0 +1,195 @@
+// Package response implements the buffering http.ResponseWriter shared by the adapter packages.
+package response
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+var (
+	// ErrBodyTooLarge is returned by Writer.Write when the body would exceed the configured maximum size.
+	ErrBodyTooLarge = errors.New("response body exceeds the maximum size")
+	// ErrClosed is returned by Writer.Write and Writer.FlushError once the response has been finalized.
+	ErrClosed = errors.New("response writer is closed")
+	// ErrHijackNotSupported is returned by Writer.Hijack as Lambda responses have no underlying connection.
+	ErrHijackNotSupported = errors.New("hijacking is not supported by Lambda responses")
+)
+
+// Writer is an http.ResponseWriter which buffers the response in memory until Result is called.
+// Unlike httptest.ResponseRecorder it enforces a maximum body size, rejects writes once the response has been
+// finalized, implements http.Flusher and is supported by http.ResponseController. Hijacking is not supported.
+// Its methods may be called concurrently, except that, as with net/http, the map returned by Header must not be used
+// concurrently with WriteHeader, Write or Flush.
+type Writer struct {
+	mu          sync.Mutex
+	header      http.Header
+	snapHeader  http.Header
+	status      int
+	wroteHeader bool
+	committed   bool
+	body        bytes.Buffer
+	maxBodySize int
+	err         error
+	closed      bool
+}
+
+var (
+	_ http.ResponseWriter = &Writer{}
+	_ http.Flusher        = &Writer{}
+	_ http.Hijacker       = &Writer{}
+	_ io.StringWriter     = &Writer{}
+)
+
+// NewWriter returns a Writer which accepts at most maxBodySize bytes of body. A maxBodySize <= 0 means no limit.
+func NewWriter(maxBodySize int) *Writer {
+	return &Writer{
+		header:      http.Header{},
+		maxBodySize: maxBodySize,
+	}
+}
+
+// Header returns the header map that will be sent by WriteHeader.
+func (w *Writer) Header() http.Header {
+	return w.header
+}
+
+// WriteHeader records the status code and a snapshot of the headers. Only the first call has any effect.
+// Informational (1xx) status codes cannot be sent by Lambda and are ignored.
+func (w *Writer) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.writeHeader(code)
+}
+
+func (w *Writer) writeHeader(code int) {
+	if code < 100 || code > 999 {
+		panic(fmt.Sprintf("invalid WriteHeader code %v", code))
+	}
+	if w.wroteHeader || w.closed || code < 200 {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	w.snapHeader = w.header.Clone()
+}
+
+// Write appends b to the buffered body, writing a 200 status first if WriteHeader has not been called.
+// As with net/http, the Content-Type is detected from the first write if it has not been set.
+// ErrBodyTooLarge is returned, and nothing is written, if b does not fit within the maximum body size.
+func (w *Writer) Write(b []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrClosed
+	}
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	w.writeHeader(http.StatusOK)
+	// FYI: As with net/http, empty writes do not commit the headers so the Content-Type is sniffed from the first
+	// non-empty write.
+	if len(b) > 0 {
+		w.commit(b)
+	}
+
+	if w.maxBodySize > 0 && w.body.Len()+len(b) > w.maxBodySize {
+		w.err = ErrBodyTooLarge
+		return 0, w.err
+	}
+
+	return w.body.Write(b)
+}
+
+// WriteString is like Write but accepts a string.
+func (w *Writer) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *Writer) commit(b []byte) {
+	if w.committed {
+		return
+	}
+	w.committed = true
+
+	SniffContentType(w.snapHeader, b)
+}
+
+// Flush implements http.Flusher. Buffered responses are only sent once the handler returns so Flush only commits
+// the status and headers.
+func (w *Writer) Flush() {
+	_ = w.FlushError()
+}
+
+// FlushError is like Flush but reports ErrClosed if the response has been finalized. It is used by
+// http.ResponseController.
+func (w *Writer) FlushError() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return ErrClosed
+	}
+
+	w.writeHeader(http.StatusOK)
+	w.commit(nil)
+
+	return nil
+}
+
+// Hijack implements http.Hijacker and always returns ErrHijackNotSupported.
+func (w *Writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return nil, nil, ErrHijackNotSupported
+}
+
+// Err returns the first error encountered while writing the body, such as ErrBodyTooLarge.
+func (w *Writer) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.err
+}
+
+// Close finalizes the response. Subsequent writes return ErrClosed.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.writeHeader(http.StatusOK)
+	w.closed = true
+
+	return nil
+}
+
+// Result closes the Writer and returns the buffered response.
+func (w *Writer) Result() *http.Response {
+	_ = w.Close()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	DropEmpty(w.snapHeader)
+
+	body := w.body.Bytes()
+	res := &http.Response{
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		StatusCode:    w.status,
+		Status:        strconv.Itoa(w.status) + " " + http.StatusText(w.status),
+		Header:        w.snapHeader,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+
+	return res
+}
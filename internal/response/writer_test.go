@@ -0,0 +1,142 @@
+package response
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter_HappyPath(t *testing.T) {
+	w := NewWriter(0)
+	http.SetCookie(w, &http.Cookie{Name: "cookie1-name", Value: "cookie1-value"})
+	w.Header().Add("key1", "val1")
+	w.WriteHeader(201)
+	w.Header().Add("key2", "ignored") // FYI: Headers changed after WriteHeader are ignored.
+	w.WriteHeader(500)                // FYI: Only the first WriteHeader has any effect.
+	_, err := w.WriteString("Hello World!")
+	assert.NoError(t, err, "failed to write string")
+
+	res := w.Result()
+
+	assert.Equal(t, 201, res.StatusCode)
+	assert.Equal(t, "201 Created", res.Status)
+	assert.Equal(t,
+		http.Header{
+			"Set-Cookie":   {"cookie1-name=cookie1-value"},
+			"Key1":         {"val1"},
+			"Content-Type": {"text/plain; charset=utf-8"},
+		},
+		res.Header)
+	assert.Equal(t, int64(12), res.ContentLength)
+
+	b, err := io.ReadAll(res.Body)
+	if !assert.NoError(t, err, "failed to read body") {
+		return
+	}
+	assert.Equal(t, []byte("Hello World!"), b)
+}
+
+func TestWriter_ImplicitStatus(t *testing.T) {
+	t.Run("Write", func(t *testing.T) {
+		w := NewWriter(0)
+		_, _ = w.Write([]byte("<html></html>"))
+
+		res := w.Result()
+
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+	})
+
+	t.Run("EmptyWrite", func(t *testing.T) {
+		w := NewWriter(0)
+		_, _ = w.Write(nil) // FYI: Empty writes do not prevent sniffing.
+		_, _ = w.Write([]byte("<html></html>"))
+
+		assert.Equal(t, "text/html; charset=utf-8", w.Result().Header.Get("Content-Type"))
+	})
+
+	t.Run("NoWrite", func(t *testing.T) {
+		w := NewWriter(0)
+
+		res := w.Result()
+
+		assert.Equal(t, 200, res.StatusCode)
+		assert.Equal(t, http.Header{}, res.Header)
+	})
+
+	t.Run("Informational", func(t *testing.T) {
+		w := NewWriter(0)
+		w.WriteHeader(103)
+		w.WriteHeader(204)
+
+		assert.Equal(t, 204, w.Result().StatusCode)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewWriter(0).WriteHeader(42)
+		})
+	})
+}
+
+func TestWriter_ExplicitContentType(t *testing.T) {
+	w := NewWriter(0)
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte("Hello World!"))
+
+	assert.Equal(t, "application/json", w.Result().Header.Get("Content-Type"))
+}
+
+func TestWriter_SuppressedContentType(t *testing.T) {
+	w := NewWriter(0)
+	w.Header()["Content-Type"] = nil
+	_, _ = w.Write([]byte("<html></html>"))
+
+	assert.Equal(t, http.Header{}, w.Result().Header)
+}
+
+func TestWriter_MaxBodySize(t *testing.T) {
+	w := NewWriter(5)
+
+	n, err := w.Write([]byte("Hello"))
+	assert.NoError(t, err, "body within limit should be written")
+	assert.Equal(t, 5, n)
+
+	n, err = w.Write([]byte("!"))
+	assert.Equal(t, ErrBodyTooLarge, err)
+	assert.Equal(t, 0, n)
+	assert.Equal(t, ErrBodyTooLarge, w.Err())
+
+	b, _ := io.ReadAll(w.Result().Body)
+	assert.Equal(t, []byte("Hello"), b)
+}
+
+func TestWriter_Closed(t *testing.T) {
+	w := NewWriter(0)
+	_ = w.Close()
+
+	_, err := w.Write([]byte("Hello"))
+	assert.Equal(t, ErrClosed, err)
+	assert.Equal(t, ErrClosed, w.FlushError())
+	assert.NoError(t, w.Err(), "writes after close should not fail the response")
+}
+
+func TestWriter_ResponseController(t *testing.T) {
+	w := NewWriter(0)
+	w.Header().Set("key1", "val1")
+	rc := http.NewResponseController(w)
+
+	assert.NoError(t, rc.Flush(), "flush should succeed")
+	w.Header().Set("key1", "ignored") // FYI: Flush commits the headers.
+
+	_, _, err := rc.Hijack()
+	assert.Equal(t, ErrHijackNotSupported, err)
+
+	res := w.Result()
+	assert.Equal(t, 200, res.StatusCode)
+	assert.Equal(t, http.Header{"Key1": {"val1"}}, res.Header)
+
+	assert.Equal(t, ErrClosed, rc.Flush())
+}
@@ -0,0 +1,179 @@
+// Package lambdaruntime implements a dependency-free client for the AWS Lambda Runtime API.
+// https://docs.aws.amazon.com/lambda/latest/dg/runtimes-api.html
+package lambdaruntime
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RuntimeAPIEnv is the environment variable Lambda uses to provide the Runtime API host and port.
+const RuntimeAPIEnv = "AWS_LAMBDA_RUNTIME_API"
+
+const apiVersion = "2018-06-01"
+
+// Invocation contains the event and metadata of a single invocation returned by Client.Next.
+type Invocation struct {
+	RequestID          string
+	Deadline           time.Time
+	InvokedFunctionARN string
+	TraceID            string
+	ClientContext      string
+	CognitoIdentity    string
+	Payload            []byte
+}
+
+// Client talks to the Lambda Runtime API.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for the Runtime API at api, which is a host and port such as "127.0.0.1:9001".
+func NewClient(api string) *Client {
+	return &Client{
+		baseURL: "http://" + api + "/" + apiVersion,
+		// FYI: No timeout is set as Next long-polls until the next invocation arrives.
+		httpClient: &http.Client{},
+	}
+}
+
+// NewClientFromEnv returns a Client for the Runtime API configured by the RuntimeAPIEnv environment variable.
+// A non-nil error will be returned if the environment variable is not set.
+func NewClientFromEnv() (*Client, error) {
+	api := os.Getenv(RuntimeAPIEnv)
+	if api == "" {
+		return nil, fmt.Errorf("%s is not set", RuntimeAPIEnv)
+	}
+	return NewClient(api), nil
+}
+
+// Next blocks until the next invocation is available and returns it.
+func (c *Client) Next(ctx context.Context) (*Invocation, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/runtime/invocation/next", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create next invocation request: %v", err)
+	}
+
+	res, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next invocation: %v", err)
+	}
+	defer res.Body.Close()
+
+	payload, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read next invocation: %v", err)
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected next invocation status %d: %s", res.StatusCode, payload)
+	}
+
+	inv := &Invocation{
+		RequestID:          res.Header.Get("Lambda-Runtime-Aws-Request-Id"),
+		InvokedFunctionARN: res.Header.Get("Lambda-Runtime-Invoked-Function-Arn"),
+		TraceID:            res.Header.Get("Lambda-Runtime-Trace-Id"),
+		ClientContext:      res.Header.Get("Lambda-Runtime-Client-Context"),
+		CognitoIdentity:    res.Header.Get("Lambda-Runtime-Cognito-Identity"),
+		Payload:            payload,
+	}
+
+	if inv.RequestID == "" {
+		return nil, fmt.Errorf("next invocation is missing a request id")
+	}
+
+	if ms := res.Header.Get("Lambda-Runtime-Deadline-Ms"); ms != "" {
+		deadline, err := strconv.ParseInt(ms, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse deadline %q: %v", ms, err)
+		}
+		inv.Deadline = time.Unix(0, deadline*int64(time.Millisecond))
+	}
+
+	return inv, nil
+}
+
+// Respond sends the successful response payload for the invocation identified by requestID.
+func (c *Client) Respond(ctx context.Context, requestID string, payload []byte) error {
+	return c.post(ctx, "/runtime/invocation/"+requestID+"/response", nil, bytes.NewReader(payload))
+}
+
+// InvocationError reports that the invocation identified by requestID failed with err.
+func (c *Client) InvocationError(ctx context.Context, requestID string, err error) error {
+	return c.postError(ctx, "/runtime/invocation/"+requestID+"/error", err)
+}
+
+// InitError reports that the function failed to initialize with err.
+// Lambda terminates the execution environment after an initialization error is reported.
+func (c *Client) InitError(ctx context.Context, err error) error {
+	return c.postError(ctx, "/runtime/init/error", err)
+}
+
+// errorResponse is the error payload understood by the Runtime API.
+type errorResponse struct {
+	ErrorMessage string `json:"errorMessage"`
+	ErrorType    string `json:"errorType"`
+}
+
+func (c *Client) postError(ctx context.Context, path string, err error) error {
+	errRes := errorResponse{
+		ErrorMessage: err.Error(),
+		ErrorType:    errorType(err),
+	}
+
+	b, err := json.Marshal(errRes)
+	if err != nil {
+		return fmt.Errorf("failed to marshal error: %v", err)
+	}
+
+	return c.post(ctx, path, http.Header{
+		"Content-Type":                       {"application/json"},
+		"Lambda-Runtime-Function-Error-Type": {errRes.ErrorType},
+	}, bytes.NewReader(b))
+}
+
+func (c *Client) post(ctx context.Context, path string, header http.Header, body io.Reader) error {
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+
+	res, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to post to %s: %v", path, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusAccepted {
+		b, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("unexpected status %d from %s: %s", res.StatusCode, path, b)
+	}
+
+	// Drain the body so the connection can be reused.
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+
+	return nil
+}
+
+// errorType returns the name of the type of err without any pointer or package prefix.
+func errorType(err error) string {
+	t := fmt.Sprintf("%T", err)
+	t = strings.TrimLeft(t, "*")
+	if i := strings.LastIndex(t, "."); i >= 0 {
+		t = t[i+1:]
+	}
+	return t
+}
@@ -0,0 +1,176 @@
+package lambdaruntime
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeRuntime is a minimal stand-in for the Lambda Runtime API.
+type fakeRuntime struct {
+	mu        sync.Mutex
+	events    []string
+	responses map[string]string
+	errors    map[string]string
+	errTypes  map[string]string
+	trailers  map[string]http.Header
+	initError string
+}
+
+func newFakeRuntime(events ...string) (*fakeRuntime, *httptest.Server) {
+	f := &fakeRuntime{
+		events:    events,
+		responses: map[string]string{},
+		errors:    map[string]string{},
+		errTypes:  map[string]string{},
+		trailers:  map[string]http.Header{},
+	}
+	return f, httptest.NewServer(f)
+}
+
+func (f *fakeRuntime) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/"+apiVersion)
+	b, _ := ioutil.ReadAll(r.Body)
+
+	switch {
+	case r.Method == http.MethodGet && path == "/runtime/invocation/next":
+		if len(f.events) == 0 {
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte("no more events"))
+			return
+		}
+		id := fmt.Sprintf("request-%d", len(f.events))
+		w.Header().Set("Lambda-Runtime-Aws-Request-Id", id)
+		w.Header().Set("Lambda-Runtime-Deadline-Ms", fmt.Sprint(time.Now().Add(time.Minute).UnixNano()/int64(time.Millisecond)))
+		w.Header().Set("Lambda-Runtime-Invoked-Function-Arn", "arn:aws:lambda:us-east-1:123456789012:function:test")
+		w.Header().Set("Lambda-Runtime-Trace-Id", "Root=1-5bef4de7-ad49b0e87f6ef6c87fc2e700")
+		_, _ = w.Write([]byte(f.events[0]))
+		f.events = f.events[1:]
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/response"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/runtime/invocation/"), "/response")
+		f.responses[id] = string(b)
+		if r.Header.Get("Lambda-Runtime-Function-Response-Mode") == "streaming" {
+			f.trailers[id] = r.Trailer
+		}
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/error") && strings.HasPrefix(path, "/runtime/invocation/"):
+		id := strings.TrimSuffix(strings.TrimPrefix(path, "/runtime/invocation/"), "/error")
+		f.errors[id] = string(b)
+		f.errTypes[id] = r.Header.Get("Lambda-Runtime-Function-Error-Type")
+		w.WriteHeader(http.StatusAccepted)
+	case r.Method == http.MethodPost && path == "/runtime/init/error":
+		f.initError = string(b)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestClient_Next(t *testing.T) {
+	_, srv := newFakeRuntime(`{"hello":"world"}`)
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	inv, err := c.Next(context.Background())
+	if !assert.NoError(t, err, "failed to get next invocation") {
+		return
+	}
+
+	assert.Equal(t, "request-1", inv.RequestID)
+	assert.Equal(t, "arn:aws:lambda:us-east-1:123456789012:function:test", inv.InvokedFunctionARN)
+	assert.Equal(t, "Root=1-5bef4de7-ad49b0e87f6ef6c87fc2e700", inv.TraceID)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), inv.Deadline, 5*time.Second)
+	assert.Equal(t, []byte(`{"hello":"world"}`), inv.Payload)
+
+	_, err = c.Next(context.Background())
+	assert.EqualError(t, err, "unexpected next invocation status 410: no more events")
+}
+
+func TestClient_Respond(t *testing.T) {
+	f, srv := newFakeRuntime()
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	err := c.Respond(context.Background(), "request-1", []byte(`{"ok":true}`))
+	if !assert.NoError(t, err, "failed to respond") {
+		return
+	}
+
+	assert.Equal(t, map[string]string{"request-1": `{"ok":true}`}, f.responses)
+}
+
+func TestClient_InvocationError(t *testing.T) {
+	f, srv := newFakeRuntime()
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	err := c.InvocationError(context.Background(), "request-1", &PanicError{Value: "boom"})
+	if !assert.NoError(t, err, "failed to report invocation error") {
+		return
+	}
+
+	assert.Equal(t, map[string]string{"request-1": `{"errorMessage":"handler panicked: boom","errorType":"PanicError"}`}, f.errors)
+	assert.Equal(t, map[string]string{"request-1": "PanicError"}, f.errTypes)
+}
+
+func TestClient_InitError(t *testing.T) {
+	f, srv := newFakeRuntime()
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	err := c.InitError(context.Background(), fmt.Errorf("boom"))
+	if !assert.NoError(t, err, "failed to report init error") {
+		return
+	}
+
+	assert.Equal(t, `{"errorMessage":"boom","errorType":"errorString"}`, f.initError)
+}
+
+func TestClient_UnexpectedStatus(t *testing.T) {
+	_, srv := newFakeRuntime()
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	err := c.post(context.Background(), "/blarg", nil, nil)
+
+	assert.EqualError(t, err, "unexpected status 404 from /blarg: ")
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	old, ok := os.LookupEnv(RuntimeAPIEnv)
+	defer func() {
+		if ok {
+			_ = os.Setenv(RuntimeAPIEnv, old)
+		} else {
+			_ = os.Unsetenv(RuntimeAPIEnv)
+		}
+	}()
+
+	_ = os.Unsetenv(RuntimeAPIEnv)
+	_, err := NewClientFromEnv()
+	assert.EqualError(t, err, "AWS_LAMBDA_RUNTIME_API is not set")
+
+	_ = os.Setenv(RuntimeAPIEnv, "127.0.0.1:9001")
+	c, err := NewClientFromEnv()
+	if !assert.NoError(t, err, "failed to create client") {
+		return
+	}
+	assert.Equal(t, "http://127.0.0.1:9001/2018-06-01", c.baseURL)
+}
@@ -0,0 +1,110 @@
+package lambdaruntime
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+)
+
+// Handler handles a single invocation and returns the response payload.
+type Handler interface {
+	Invoke(ctx context.Context, payload []byte) ([]byte, error)
+}
+
+// HandlerFunc is an adapter to allow the use of ordinary functions as a Handler.
+type HandlerFunc func(ctx context.Context, payload []byte) ([]byte, error)
+
+// Invoke calls f(ctx, payload).
+func (f HandlerFunc) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	return f(ctx, payload)
+}
+
+type contextKey int
+
+const invocationKey contextKey = iota
+
+// InvocationFrom returns the *Invocation being handled, if any, from a Handler's context.
+func InvocationFrom(ctx context.Context) (*Invocation, bool) {
+	inv, ok := ctx.Value(invocationKey).(*Invocation)
+	return inv, ok
+}
+
+// Serve runs the invocation loop: it receives invocations from c, passes them to h, and reports the results.
+// Serve only returns when an invocation cannot be received or its result cannot be reported.
+// Handler errors are reported as invocation errors and do not stop the loop. A panicking Handler is reported as an
+// invocation error after which Serve returns as the process state may no longer be consistent.
+func Serve(ctx context.Context, c *Client, h Handler) error {
+	for {
+		inv, err := c.Next(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := handle(ctx, c, h, inv); err != nil {
+			return err
+		}
+	}
+}
+
+// Start runs Serve using a Client configured from the environment. Start never returns; it exits the process if the
+// invocation loop stops.
+func Start(h Handler) {
+	c, err := NewClientFromEnv()
+	if err != nil {
+		log.Fatalf("failed to create runtime client: %v", err)
+	}
+
+	log.Fatal(Serve(context.Background(), c, h))
+}
+
+// invocationContext returns a context carrying inv and its deadline.
+func invocationContext(ctx context.Context, inv *Invocation) (context.Context, context.CancelFunc) {
+	// Mirror the official runtimes so the X-Ray SDK can pick up the trace id.
+	if inv.TraceID != "" {
+		_ = os.Setenv("_X_AMZN_TRACE_ID", inv.TraceID)
+	}
+
+	ctx = context.WithValue(ctx, invocationKey, inv)
+	if inv.Deadline.IsZero() {
+		return context.WithCancel(ctx)
+	}
+	return context.WithDeadline(ctx, inv.Deadline)
+}
+
+func handle(ctx context.Context, c *Client, h Handler, inv *Invocation) error {
+	invCtx, cancel := invocationContext(ctx, inv)
+	defer cancel()
+
+	payload, err := invoke(invCtx, h, inv.Payload)
+	if err != nil {
+		if rErr := c.InvocationError(ctx, inv.RequestID, err); rErr != nil {
+			return rErr
+		}
+		if _, ok := err.(*PanicError); ok {
+			return err
+		}
+		return nil
+	}
+
+	return c.Respond(ctx, inv.RequestID, payload)
+}
+
+// PanicError is reported as the invocation error when a Handler panics.
+type PanicError struct {
+	Value interface{}
+}
+
+// Error implements error.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("handler panicked: %v", e.Value)
+}
+
+func invoke(ctx context.Context, h Handler, payload []byte) (res []byte, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v}
+		}
+	}()
+	return h.Invoke(ctx, payload)
+}
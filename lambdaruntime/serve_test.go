@@ -0,0 +1,58 @@
+package lambdaruntime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServe(t *testing.T) {
+	f, srv := newFakeRuntime("fail", "hello", "panic")
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	err := Serve(context.Background(), c, HandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		inv, ok := InvocationFrom(ctx)
+		assert.True(t, ok, "invocation should be in the context")
+		assert.Equal(t, payload, inv.Payload)
+
+		_, ok = ctx.Deadline()
+		assert.True(t, ok, "context should have the invocation deadline")
+
+		switch string(payload) {
+		case "fail":
+			return nil, fmt.Errorf("boom")
+		case "panic":
+			panic("boom")
+		default:
+			return []byte(strings.ToUpper(string(payload))), nil
+		}
+	}))
+
+	assert.EqualError(t, err, "handler panicked: boom")
+	assert.Equal(t, map[string]string{"request-2": "HELLO"}, f.responses)
+	assert.Equal(t,
+		map[string]string{
+			"request-3": `{"errorMessage":"boom","errorType":"errorString"}`,
+			"request-1": `{"errorMessage":"handler panicked: boom","errorType":"PanicError"}`,
+		},
+		f.errors)
+}
+
+func TestServe_NextError(t *testing.T) {
+	_, srv := newFakeRuntime()
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	err := Serve(context.Background(), c, HandlerFunc(func(ctx context.Context, payload []byte) ([]byte, error) {
+		t.Error("handler should not be called")
+		return nil, nil
+	}))
+
+	assert.EqualError(t, err, "unexpected next invocation status 410: no more events")
+}
@@ -0,0 +1,162 @@
+package lambdaruntime
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// HTTPIntegrationResponseContentType is the Content-Type of streamed responses which start with the HTTP integration
+// prelude (status code, headers and cookies) followed by the body. It is understood by Lambda Function URLs.
+// https://docs.aws.amazon.com/lambda/latest/dg/configuration-response-streaming.html
+const HTTPIntegrationResponseContentType = "application/vnd.awslambda.http-integration-response"
+
+// StreamHandler handles a single invocation by streaming the response payload to w.
+type StreamHandler interface {
+	// ContentType returns the Content-Type of the streamed responses.
+	ContentType() string
+	// InvokeStream handles the invocation. Each write to w is sent to the Runtime API immediately.
+	InvokeStream(ctx context.Context, payload []byte, w io.Writer) error
+}
+
+// RespondStream streams the response for the invocation identified by requestID. fn is called with a writer whose
+// writes are sent to the Runtime API as they happen. If fn returns an error it is reported as a mid-stream error.
+func (c *Client) RespondStream(ctx context.Context, requestID string, contentType string, fn func(w io.Writer) error) error {
+	pr, pw := io.Pipe()
+	body := &startReader{r: pr, started: make(chan struct{})}
+
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/runtime/invocation/"+requestID+"/response", body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Lambda-Runtime-Function-Response-Mode", "streaming")
+	req.TransferEncoding = []string{"chunked"}
+	// FYI: Trailer values are set once fn returns and are sent after the body.
+	req.Trailer = http.Header{
+		"Lambda-Runtime-Function-Error-Type": nil,
+		"Lambda-Runtime-Function-Error-Body": nil,
+	}
+
+	done := make(chan struct{})
+	fnDone := make(chan struct{})
+	go func() {
+		defer close(fnDone)
+
+		// The transport reads the trailers before sending the request so only start once it reads the body.
+		select {
+		case <-body.started:
+		case <-done:
+			return
+		}
+
+		if err := fn(pw); err != nil {
+			errRes := errorResponse{ErrorMessage: err.Error(), ErrorType: errorType(err)}
+			b, _ := json.Marshal(errRes)
+			req.Trailer.Set("Lambda-Runtime-Function-Error-Type", errRes.ErrorType)
+			req.Trailer.Set("Lambda-Runtime-Function-Error-Body", base64.StdEncoding.EncodeToString(b))
+		}
+		_ = pw.Close()
+	}()
+
+	res, err := c.httpClient.Do(req.WithContext(ctx))
+	close(done)
+	if err != nil {
+		// Unblock fn if the request failed before the body was consumed.
+		_ = pr.CloseWithError(err)
+		<-fnDone
+		return fmt.Errorf("failed to stream response: %v", err)
+	}
+	defer res.Body.Close()
+
+	// Unblock fn if the Runtime API answered before reading the whole body, e.g. with 413 Request Entity Too Large.
+	// Once the body has been read fn has already returned so this is a no-op.
+	_ = pr.CloseWithError(errStreamClosed)
+	<-fnDone
+
+	if res.StatusCode != http.StatusAccepted {
+		b, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("unexpected status %d from streamed response: %s", res.StatusCode, b)
+	}
+
+	_, _ = io.Copy(ioutil.Discard, res.Body)
+
+	return nil
+}
+
+// errStreamClosed is returned by the writes of a streamed response once the request body has been closed.
+var errStreamClosed = errors.New("response stream is closed")
+
+// startReader closes started on the first call to Read. Closing it closes the pipe so writes to the other end fail
+// instead of blocking once the transport stops reading.
+type startReader struct {
+	r       *io.PipeReader
+	once    sync.Once
+	started chan struct{}
+}
+
+// Read closes started on the first call and reads from the pipe.
+func (s *startReader) Read(p []byte) (int, error) {
+	s.once.Do(func() { close(s.started) })
+	return s.r.Read(p)
+}
+
+// Close closes the pipe with errStreamClosed.
+func (s *startReader) Close() error {
+	return s.r.CloseWithError(errStreamClosed)
+}
+
+// ServeStream is like Serve but streams responses using h.
+func ServeStream(ctx context.Context, c *Client, h StreamHandler) error {
+	for {
+		inv, err := c.Next(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := handleStream(ctx, c, h, inv); err != nil {
+			return err
+		}
+	}
+}
+
+// StartStream is like Start but streams responses using h.
+func StartStream(h StreamHandler) {
+	c, err := NewClientFromEnv()
+	if err != nil {
+		log.Fatalf("failed to create runtime client: %v", err)
+	}
+
+	log.Fatal(ServeStream(context.Background(), c, h))
+}
+
+func handleStream(ctx context.Context, c *Client, h StreamHandler, inv *Invocation) error {
+	invCtx, cancel := invocationContext(ctx, inv)
+	defer cancel()
+
+	var panicErr *PanicError
+	err := c.RespondStream(ctx, inv.RequestID, h.ContentType(), func(w io.Writer) (err error) {
+		defer func() {
+			if v := recover(); v != nil {
+				panicErr = &PanicError{Value: v}
+				err = panicErr
+			}
+		}()
+		return h.InvokeStream(invCtx, inv.Payload, w)
+	})
+	if err != nil {
+		return err
+	}
+	if panicErr != nil {
+		return panicErr
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package lambdaruntime
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStreamHandler func(ctx context.Context, payload []byte, w io.Writer) error
+
+func (h testStreamHandler) ContentType() string {
+	return "text/plain"
+}
+
+func (h testStreamHandler) InvokeStream(ctx context.Context, payload []byte, w io.Writer) error {
+	return h(ctx, payload, w)
+}
+
+func TestClient_RespondStream(t *testing.T) {
+	f, srv := newFakeRuntime()
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	t.Run("HappyPath", func(t *testing.T) {
+		err := c.RespondStream(context.Background(), "request-1", "text/plain", func(w io.Writer) error {
+			_, _ = io.WriteString(w, "Hello ")
+			_, _ = io.WriteString(w, "World!")
+			return nil
+		})
+		if !assert.NoError(t, err, "failed to stream response") {
+			return
+		}
+
+		assert.Equal(t, "Hello World!", f.responses["request-1"])
+		assert.Equal(t, "", f.trailers["request-1"].Get("Lambda-Runtime-Function-Error-Type"))
+	})
+
+	t.Run("MidStreamError", func(t *testing.T) {
+		err := c.RespondStream(context.Background(), "request-2", "text/plain", func(w io.Writer) error {
+			_, _ = io.WriteString(w, "Hello ")
+			return fmt.Errorf("boom")
+		})
+		if !assert.NoError(t, err, "failed to stream response") {
+			return
+		}
+
+		assert.Equal(t, "Hello ", f.responses["request-2"])
+		assert.Equal(t,
+			http.Header{
+				"Lambda-Runtime-Function-Error-Type": {"errorString"},
+				"Lambda-Runtime-Function-Error-Body": {base64.StdEncoding.EncodeToString([]byte(`{"errorMessage":"boom","errorType":"errorString"}`))},
+			},
+			f.trailers["request-2"])
+	})
+}
+
+func TestClient_RespondStream_EarlyResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		_, _ = io.WriteString(w, "too large")
+	}))
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- c.RespondStream(context.Background(), "request-1", "text/plain", func(w io.Writer) error {
+			chunk := make([]byte, 64*1024)
+			for {
+				if _, err := w.Write(chunk); err != nil {
+					return err
+				}
+			}
+		})
+	}()
+
+	select {
+	case err := <-errc:
+		assert.EqualError(t, err, "unexpected status 413 from streamed response: too large")
+	case <-time.After(10 * time.Second):
+		t.Fatal("RespondStream did not return after the early response")
+	}
+}
+
+func TestServeStream(t *testing.T) {
+	f, srv := newFakeRuntime("hello", "panic")
+	defer srv.Close()
+
+	c := NewClient(strings.TrimPrefix(srv.URL, "http://"))
+
+	err := ServeStream(context.Background(), c, testStreamHandler(func(ctx context.Context, payload []byte, w io.Writer) error {
+		_, ok := InvocationFrom(ctx)
+		assert.True(t, ok, "invocation should be in the context")
+
+		if string(payload) == "panic" {
+			panic("boom")
+		}
+		_, err := w.Write([]byte(strings.ToUpper(string(payload))))
+		return err
+	}))
+
+	assert.EqualError(t, err, "handler panicked: boom")
+	assert.Equal(t, map[string]string{"request-2": "HELLO", "request-1": ""}, f.responses)
+	assert.Equal(t, "PanicError", f.trailers["request-1"].Get("Lambda-Runtime-Function-Error-Type"))
+}
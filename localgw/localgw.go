@@ -0,0 +1,125 @@
+// Package localgw emulates API Gateway locally. It converts incoming HTTP requests into API Gateway events, invokes a
+// Lambda handler in-process, and writes the handler's response back onto the wire.
+//
+// Events and responses are marshalled to and from JSON exactly as they are by Lambda so handlers exercise the same code
+// path locally as they do in production.
+package localgw
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"harrisonhjones.com/go-apigw-http-adapter/httpadapter"
+	"harrisonhjones.com/go-apigw-http-adapter/lambdaruntime"
+	"harrisonhjones.com/go-apigw-http-adapter/restadapter"
+)
+
+// HTTPAPI returns an http.Handler which emulates an HTTP API using payload format version 2.0 in front of h, e.g. a
+// handler returned by httpadapter.Handler.
+func HTTPAPI(h lambdaruntime.Handler) http.Handler {
+	return &gateway{
+		h:             h,
+		newEvent:      func(r *http.Request) (interface{}, error) { return httpadapter.NewRequestFromHTTP(r) },
+		writeResponse: writeHTTPAPIResponse,
+	}
+}
+
+// RESTAPI returns an http.Handler which emulates a REST API using payload format version 1.0 in front of h, e.g. a
+// handler returned by restadapter.Handler.
+func RESTAPI(h lambdaruntime.Handler) http.Handler {
+	return &gateway{
+		h:             h,
+		newEvent:      func(r *http.Request) (interface{}, error) { return restadapter.NewRequestFromHTTP(r) },
+		writeResponse: writeRESTAPIResponse,
+	}
+}
+
+// gateway converts HTTP requests into events for h and its responses back into HTTP responses.
+type gateway struct {
+	h             lambdaruntime.Handler
+	newEvent      func(r *http.Request) (interface{}, error)
+	writeResponse func(w http.ResponseWriter, payload []byte) error
+}
+
+// ServeHTTP invokes h with the event for r and writes its response. Failed invocations and invalid responses are
+// logged and answered with a 500 Internal Server Error, as API Gateway does.
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := g.newEvent(r)
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+
+	event, err := json.Marshal(req)
+	if err != nil {
+		log.Printf("localgw: failed to marshal event: %v", err)
+		writeInternalServerError(w)
+		return
+	}
+
+	payload, err := g.h.Invoke(r.Context(), event)
+	if err != nil {
+		// API Gateway hides invocation errors from the client.
+		log.Printf("localgw: invocation failed: %v", err)
+		writeInternalServerError(w)
+		return
+	}
+
+	if err := g.writeResponse(w, payload); err != nil {
+		log.Printf("localgw: invalid response: %v", err)
+		writeInternalServerError(w)
+		return
+	}
+}
+
+func writeInternalServerError(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write([]byte(`{"message":"Internal Server Error"}`))
+}
+
+func writeHTTPAPIResponse(w http.ResponseWriter, payload []byte) error {
+	var res httpadapter.Response
+	if err := json.Unmarshal(payload, &res); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	httpRes, err := res.ToHTTP()
+	if err != nil {
+		return err
+	}
+
+	return writeResponse(w, httpRes)
+}
+
+func writeRESTAPIResponse(w http.ResponseWriter, payload []byte) error {
+	var res restadapter.Response
+	if err := json.Unmarshal(payload, &res); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %v", err)
+	}
+
+	httpRes, err := res.ToHTTP()
+	if err != nil {
+		return err
+	}
+
+	return writeResponse(w, httpRes)
+}
+
+// writeResponse copies res onto the wire.
+func writeResponse(w http.ResponseWriter, res *http.Response) error {
+	if res.StatusCode < 100 || res.StatusCode > 999 {
+		return fmt.Errorf("invalid status code %d", res.StatusCode)
+	}
+
+	for k, v := range res.Header {
+		w.Header()[k] = v
+	}
+	w.WriteHeader(res.StatusCode)
+	_, _ = io.Copy(w, res.Body)
+
+	return nil
+}
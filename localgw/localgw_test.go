@@ -0,0 +1,91 @@
+package localgw
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"harrisonhjones.com/go-apigw-http-adapter/httpadapter"
+	"harrisonhjones.com/go-apigw-http-adapter/lambdaruntime"
+	"harrisonhjones.com/go-apigw-http-adapter/restadapter"
+)
+
+// echo writes the request back to the client in a stable format.
+func echo(w http.ResponseWriter, r *http.Request) {
+	b, _ := ioutil.ReadAll(r.Body)
+	ck, _ := r.Cookie("cookie1")
+
+	http.SetCookie(w, &http.Cookie{Name: "cookie2", Value: "val2"})
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Key1", "val1")
+	w.WriteHeader(201)
+	_, _ = fmt.Fprintf(w, "%s %s %s %v %s %q", r.Method, r.URL.Path, r.URL.Query()["p"], ck, r.Header.Get("Header1"), b)
+}
+
+func testGateway(t *testing.T, gw http.Handler) {
+	srv := httptest.NewServer(gw)
+	defer srv.Close()
+
+	req, err := http.NewRequest("POST", srv.URL+"/my/path?p=1&p=2", strings.NewReader("\x00\x01binary"))
+	if !assert.NoError(t, err, "failed to create request") {
+		return
+	}
+	req.Header.Set("Header1", "value1")
+	req.AddCookie(&http.Cookie{Name: "cookie1", Value: "val1"})
+
+	res, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err, "failed to send request") {
+		return
+	}
+	defer res.Body.Close()
+
+	b, err := ioutil.ReadAll(res.Body)
+	if !assert.NoError(t, err, "failed to read response body") {
+		return
+	}
+
+	assert.Equal(t, 201, res.StatusCode)
+	assert.Equal(t, "val1", res.Header.Get("Key1"))
+	assert.Equal(t, []string{"cookie2=val2"}, res.Header["Set-Cookie"])
+	assert.Equal(t, `POST /my/path [1 2] cookie1=val1 value1 "\x00\x01binary"`, string(b))
+}
+
+func TestHTTPAPI(t *testing.T) {
+	testGateway(t, HTTPAPI(httpadapter.Handler(http.HandlerFunc(echo), httpadapter.WithEncoding(func(*http.Response) bool {
+		return true
+	}))))
+}
+
+func TestRESTAPI(t *testing.T) {
+	testGateway(t, RESTAPI(restadapter.Handler(http.HandlerFunc(echo), restadapter.WithEncoding(func(*http.Response) bool {
+		return true
+	}))))
+}
+
+func TestGateway_InvocationError(t *testing.T) {
+	for name, h := range map[string]lambdaruntime.HandlerFunc{
+		"Error": func(ctx context.Context, payload []byte) ([]byte, error) {
+			return nil, fmt.Errorf("boom")
+		},
+		"InvalidResponse": func(ctx context.Context, payload []byte) ([]byte, error) {
+			return []byte(`blarg`), nil
+		},
+		"MissingStatusCode": func(ctx context.Context, payload []byte) ([]byte, error) {
+			return []byte(`{}`), nil
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			HTTPAPI(h).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+			assert.Equal(t, 500, rec.Code)
+			assert.Equal(t, `{"message":"Internal Server Error"}`, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package restadapter
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// RequestContextAuthorizer contains the output of the authorizer of the request, if any. It can be retrieved using
+// AuthorizerFrom. Requests authorized using AWS_IAM have no authorizer data, see RequestContextIdentity instead.
+type RequestContextAuthorizer struct {
+	// PrincipalID is the principal returned by a Lambda authorizer.
+	PrincipalID string
+	// IntegrationLatency is the authorizer latency in milliseconds.
+	IntegrationLatency int
+	// Claims are the claims of an Amazon Cognito user pool authorizer.
+	Claims map[string]interface{}
+	// Scopes are the OAuth scopes of an Amazon Cognito user pool authorizer.
+	Scopes []string
+	// Context is the context returned by a Lambda authorizer, i.e. every other key.
+	Context map[string]interface{}
+}
+
+// authorizerKeys are the keys of RequestContextAuthorizer which are not part of its Context.
+var authorizerKeys = map[string]bool{
+	"principalId":        true,
+	"integrationLatency": true,
+	"claims":             true,
+	"scopes":             true,
+}
+
+// UnmarshalJSON implements json.Unmarshaler. API Gateway merges the context returned by Lambda authorizers into the
+// authorizer object so every unknown key is stored in Context.
+func (a *RequestContextAuthorizer) UnmarshalJSON(b []byte) error {
+	var known struct {
+		PrincipalID        string                 `json:"principalId"`
+		IntegrationLatency int                    `json:"integrationLatency"`
+		Claims             map[string]interface{} `json:"claims"`
+		Scopes             []string               `json:"scopes"`
+	}
+	if err := json.Unmarshal(b, &known); err != nil {
+		return fmt.Errorf("failed to unmarshal authorizer: %v", err)
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(b, &all); err != nil {
+		return fmt.Errorf("failed to unmarshal authorizer: %v", err)
+	}
+
+	*a = RequestContextAuthorizer{
+		PrincipalID:        known.PrincipalID,
+		IntegrationLatency: known.IntegrationLatency,
+		Claims:             known.Claims,
+		Scopes:             known.Scopes,
+	}
+	for k, v := range all {
+		if authorizerKeys[k] {
+			continue
+		}
+		if a.Context == nil {
+			a.Context = map[string]interface{}{}
+		}
+		a.Context[k] = v
+	}
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. It is the inverse of UnmarshalJSON.
+func (a RequestContextAuthorizer) MarshalJSON() ([]byte, error) {
+	all := map[string]interface{}{}
+	for k, v := range a.Context {
+		all[k] = v
+	}
+	if a.PrincipalID != "" {
+		all["principalId"] = a.PrincipalID
+	}
+	if a.IntegrationLatency != 0 {
+		all["integrationLatency"] = a.IntegrationLatency
+	}
+	if a.Claims != nil {
+		all["claims"] = a.Claims
+	}
+	if a.Scopes != nil {
+		all["scopes"] = a.Scopes
+	}
+	return json.Marshal(all)
+}
@@ -0,0 +1,83 @@
+package restadapter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestContextAuthorizer_JSON(t *testing.T) {
+	t.Run("Lambda", func(t *testing.T) {
+		var auth RequestContextAuthorizer
+		err := json.Unmarshal([]byte(`{"principalId":"user1","integrationLatency":12,"tenant":"tenant1","admin":true}`), &auth)
+		if !assert.NoError(t, err, "failed to unmarshal authorizer") {
+			return
+		}
+
+		assert.Equal(t,
+			RequestContextAuthorizer{
+				PrincipalID:        "user1",
+				IntegrationLatency: 12,
+				Context:            map[string]interface{}{"tenant": "tenant1", "admin": true},
+			},
+			auth)
+
+		b, err := json.Marshal(auth)
+		if !assert.NoError(t, err, "failed to marshal authorizer") {
+			return
+		}
+		assert.JSONEq(t, `{"principalId":"user1","integrationLatency":12,"tenant":"tenant1","admin":true}`, string(b))
+	})
+
+	t.Run("Cognito", func(t *testing.T) {
+		var auth RequestContextAuthorizer
+		err := json.Unmarshal([]byte(`{"claims":{"sub":"user1","email_verified":"true"},"scopes":["read"]}`), &auth)
+		if !assert.NoError(t, err, "failed to unmarshal authorizer") {
+			return
+		}
+
+		assert.Equal(t,
+			RequestContextAuthorizer{
+				Claims: map[string]interface{}{"sub": "user1", "email_verified": "true"},
+				Scopes: []string{"read"},
+			},
+			auth)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		var auth RequestContextAuthorizer
+		err := json.Unmarshal([]byte(`{"principalId":1}`), &auth)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestAuthorizerFrom(t *testing.T) {
+	var req Request
+	err := json.Unmarshal([]byte(`{"httpMethod":"GET","path":"/","requestContext":{"authorizer":{"principalId":"user1"}}}`), &req)
+	if !assert.NoError(t, err, "failed to unmarshal request") {
+		return
+	}
+
+	httpReq, err := TransformRequest(context.Background(), &req)
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	auth, ok := AuthorizerFrom(httpReq.Context())
+	if assert.True(t, ok) {
+		assert.Equal(t, "user1", auth.PrincipalID)
+	}
+
+	_, ok = AuthorizerFrom(context.Background())
+	assert.False(t, ok)
+
+	httpReq, err = TransformRequest(context.Background(), &Request{HTTPMethod: "GET", Path: "/"})
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+	_, ok = AuthorizerFrom(httpReq.Context())
+	assert.False(t, ok)
+}
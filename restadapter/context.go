@@ -0,0 +1,52 @@
+package restadapter
+
+import "context"
+
+type contextKey int
+
+const (
+	requestContextKey contextKey = iota
+	pathInfoKey
+	stageVariablesKey
+)
+
+func withRequestContext(ctx context.Context, reqCtx RequestContext) context.Context {
+	return context.WithValue(ctx, requestContextKey, reqCtx)
+}
+
+// RequestContextFrom returns the RequestContext of the Request from the context of a transformed http.Request.
+func RequestContextFrom(ctx context.Context) (RequestContext, bool) {
+	reqCtx, ok := ctx.Value(requestContextKey).(RequestContext)
+	return reqCtx, ok
+}
+
+// AuthorizerFrom returns the authorizer data from the context of a transformed http.Request. It returns false if the
+// request was not authorized by a Lambda or Amazon Cognito user pool authorizer.
+func AuthorizerFrom(ctx context.Context) (*RequestContextAuthorizer, bool) {
+	reqCtx, ok := RequestContextFrom(ctx)
+	if !ok || reqCtx.Authorizer == nil {
+		return nil, false
+	}
+	return reqCtx.Authorizer, true
+}
+
+func withPathInfo(ctx context.Context, info PathInfo) context.Context {
+	return context.WithValue(ctx, pathInfoKey, info)
+}
+
+// PathInfoFrom returns the PathInfo of the Request from the context of a transformed http.Request.
+func PathInfoFrom(ctx context.Context) (PathInfo, bool) {
+	info, ok := ctx.Value(pathInfoKey).(PathInfo)
+	return info, ok
+}
+
+func withStageVariables(ctx context.Context, vars map[string]string) context.Context {
+	return context.WithValue(ctx, stageVariablesKey, vars)
+}
+
+// StageVariablesFrom returns the stage variables of the Request from the context of a transformed http.Request. It
+// returns false if the stage has no stage variables.
+func StageVariablesFrom(ctx context.Context) (map[string]string, bool) {
+	vars, ok := ctx.Value(stageVariablesKey).(map[string]string)
+	return vars, ok && vars != nil
+}
@@ -0,0 +1,118 @@
+package restadapter
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadRequest(t *testing.T, name string) *Request {
+	t.Helper()
+
+	b, err := ioutil.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+
+	var req Request
+	if err := json.Unmarshal(b, &req); err != nil {
+		t.Fatalf("failed to unmarshal fixture: %v", err)
+	}
+
+	return &req
+}
+
+// clientCertFile is the self-signed client certificate of the mutual TLS tests. It is also embedded in restapi-get.json.
+const clientCertFile = "testdata/client.pem"
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	return string(b)
+}
+
+func TestRequestContextFrom(t *testing.T) {
+	req := loadRequest(t, "restapi-get.json")
+
+	var reqCtx RequestContext
+	var ok bool
+	_, err := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCtx, ok = RequestContextFrom(r.Context())
+	}))(context.Background(), *req)
+	if !assert.NoError(t, err, "failed to handle request") {
+		return
+	}
+
+	if !assert.True(t, ok, "missing request context") {
+		return
+	}
+	assert.Equal(t,
+		RequestContext{
+			AccountID:         "123456789012",
+			APIID:             "api-id",
+			DomainName:        "id.execute-api.us-east-1.amazonaws.com",
+			DomainPrefix:      "id",
+			ExtendedRequestID: "request-id",
+			HTTPMethod:        "GET",
+			Identity: RequestContextIdentity{
+				APIKey:   "api-key",
+				APIKeyID: "api-key-id",
+				ClientCert: &ClientCert{
+					ClientCertPEM: readFile(t, clientCertFile),
+					SubjectDN:     "www.example.com",
+					IssuerDN:      "Example issuer",
+					SerialNumber:  "a1:a1:a1:a1:a1:a1:a1:a1:a1:a1:a1:a1:a1:a1:a1:a1",
+					Validity: ClientCertValidity{
+						NotBefore: "May 28 12:30:02 2019 GMT",
+						NotAfter:  "Aug  5 09:36:04 2021 GMT",
+					},
+				},
+				SourceIP:  "192.0.2.1",
+				UserAgent: "agent",
+			},
+			Path:             "/prod/my/path",
+			Protocol:         "HTTP/1.1",
+			RequestID:        "id=",
+			RequestTime:      "04/Mar/2020:19:15:17 +0000",
+			RequestTimeEpoch: 1583349317135,
+			ResourceID:       "abcdef",
+			ResourcePath:     "/my/{proxy+}",
+			Stage:            "prod",
+		},
+		reqCtx)
+}
+
+func TestRequestContextFrom_Missing(t *testing.T) {
+	_, ok := RequestContextFrom(context.Background())
+	assert.False(t, ok)
+}
+
+func TestStageVariablesFrom(t *testing.T) {
+	req := loadRequest(t, "restapi-get.json")
+
+	httpReq, err := TransformRequest(context.Background(), req)
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	vars, ok := StageVariablesFrom(httpReq.Context())
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"stageVariable1": "value1"}, vars)
+
+	req.StageVariables = nil
+	httpReq, err = TransformRequest(context.Background(), req)
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	_, ok = StageVariablesFrom(httpReq.Context())
+	assert.False(t, ok)
+}
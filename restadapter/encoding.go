@@ -0,0 +1,16 @@
+package restadapter
+
+import "harrisonhjones.com/go-apigw-http-adapter/internal/response"
+
+// EncodingPolicy decides whether TransformResponse base64 encodes the body of a REST API response.
+type EncodingPolicy = response.EncodingPolicy
+
+// BinaryMediaTypes returns an EncodingPolicy which mirrors the binaryMediaTypes of the REST API, e.g. "image/png" or
+// "image/*".
+func BinaryMediaTypes(mediaTypes ...string) EncodingPolicy {
+	return response.BinaryMediaTypes(mediaTypes...)
+}
+
+// DefaultEncodingPolicy is used by Handler unless WithEncoding is given. It encodes every response which is not text,
+// e.g. an image or a gzip-encoded body. REST APIs only decode it if its Content-Type is one of their binaryMediaTypes.
+var DefaultEncodingPolicy EncodingPolicy = response.DefaultEncodingPolicy
@@ -0,0 +1,160 @@
+package restadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_HappyPath(t *testing.T) {
+	req := Request{
+		Path:       "/my/path",
+		HTTPMethod: "POST",
+		MultiValueHeaders: map[string][]string{
+			"Header1": {"value1"},
+		},
+		MultiValueQueryStringParameters: map[string][]string{
+			"name": {"World"},
+		},
+		RequestContext: RequestContext{
+			DomainName: "example.com",
+		},
+		Body: "Hello Body!",
+	}
+
+	tstCtx := context.Background()
+
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqCtx, ok := RequestContextFrom(r.Context())
+		assert.True(t, ok, "missing request context")
+		assert.Equal(t, req.RequestContext, reqCtx)
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/my/path", r.URL.Path)
+		assert.Equal(t, "value1", r.Header.Get("Header1"))
+
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err, "failed to read body")
+		assert.Equal(t, "Hello Body!", string(b))
+
+		http.SetCookie(w, &http.Cookie{Name: "cookie1", Value: "val1"})
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(201)
+		_, _ = fmt.Fprintf(w, "Hello %s!", r.URL.Query().Get("name"))
+	})
+
+	t.Run("NotEncoded", func(t *testing.T) {
+		res, err := Handler(mux)(tstCtx, req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t,
+			&Response{
+				StatusCode: 201,
+				MultiValueHeaders: map[string][]string{
+					"Content-Type": {"text/plain"},
+					"Set-Cookie":   {"cookie1=val1"},
+				},
+				Body: "Hello World!",
+			},
+			res)
+	})
+
+	t.Run("Encoded", func(t *testing.T) {
+		h := Handler(mux, WithEncoding(func(*http.Response) bool { return true }))
+
+		res, err := h(tstCtx, req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, "SGVsbG8gV29ybGQh", res.Body) // FYI: base64.StdEncoding.EncodeToString([]byte("Hello World!"))
+		assert.True(t, res.IsBase64Encoded)
+	})
+}
+
+func TestHandler_TransformRequestError(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		_, err := Handler(h)(context.Background(), Request{IsBase64Encoded: true, Body: "blarg"})
+
+		assert.EqualError(t, err, "failed to decode body: illegal base64 data at input byte 4")
+	})
+
+	t.Run("WithErrorHandler", func(t *testing.T) {
+		res, err := Handler(h, WithErrorHandler(func(ctx context.Context, err error) (*Response, error) {
+			return &Response{StatusCode: 400, Body: err.Error()}, nil
+		}))(context.Background(), Request{IsBase64Encoded: true, Body: "blarg"})
+
+		if !assert.NoError(t, err, "error handler error should be returned") {
+			return
+		}
+		assert.Equal(t, &Response{StatusCode: 400, Body: "failed to decode body: illegal base64 data at input byte 4"}, res)
+	})
+}
+
+func TestHandlerFunc_Invoke(t *testing.T) {
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s %s", r.Method, r.URL.Path)
+	}))
+
+	t.Run("HappyPath", func(t *testing.T) {
+		res, err := h.Invoke(context.Background(), []byte(`{
+			"path": "/my/path",
+			"httpMethod": "GET",
+			"requestContext": {"domainName": "example.com"}
+		}`))
+		if !assert.NoError(t, err, "failed to invoke handler") {
+			return
+		}
+
+		assert.JSONEq(t,
+			`{
+				"statusCode": 200,
+				"multiValueHeaders": {"Content-Type": ["text/plain; charset=utf-8"]},
+				"body": "GET /my/path"
+			}`,
+			string(res))
+	})
+
+	t.Run("BadPayload", func(t *testing.T) {
+		_, err := h.Invoke(context.Background(), []byte(`blarg`))
+
+		assert.EqualError(t, err, "failed to unmarshal request: invalid character 'b' looking for beginning of value")
+	})
+}
+
+func TestHandler_MaxBodySize(t *testing.T) {
+	req := Request{HTTPMethod: "GET", Path: "/", RequestContext: RequestContext{DomainName: "example.com"}}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte("Hello World!"))
+		assert.Equal(t, ErrBodyTooLarge, err)
+	})
+
+	_, err := Handler(h, WithMaxBodySize(5))(context.Background(), req)
+
+	assert.True(t, errors.Is(err, ErrBodyTooLarge), "error should wrap ErrBodyTooLarge")
+	assert.EqualError(t, err, "failed to write response body: response body exceeds the maximum size")
+}
+
+func TestHandler_ResponseOption(t *testing.T) {
+	req := Request{HTTPMethod: "GET", Path: "/", RequestContext: RequestContext{DomainName: "example.com"}}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte{'a', 0xff})
+	})
+
+	_, err := Handler(h, WithUTF8Mode(UTF8Strict))(context.Background(), req)
+
+	assert.EqualError(t, err, "response body is not valid UTF-8 at byte 1")
+}
@@ -0,0 +1,96 @@
+package restadapter
+
+import (
+	"net/http"
+	"strings"
+)
+
+// PathMode configures which path TransformRequest uses for the http.Request URL.
+type PathMode int
+
+const (
+	// PathModeResource uses the path relative to the API, without the stage or base path, e.g. "/items". This is the
+	// default.
+	PathModeResource PathMode = iota
+	// PathModePublic uses the path requested by the client, including the stage or base path, e.g. "/prod/items".
+	PathModePublic
+)
+
+// PathInfo describes the paths of a Request. It is stored in the context of the transformed http.Request and can be
+// retrieved using PathInfoFrom.
+//
+// Requests to the execute-api endpoint arrive with a Path of "/items" and a RequestContext.Path of "/prod/items".
+// Requests to a custom domain name with a base path mapping of "v1" arrive with both set to "/v1/items"; use
+// WithBasePath to strip the base path.
+type PathInfo struct {
+	// Public is the path requested by the client, e.g. "/prod/items" or "/v1/items".
+	Public string
+	// Resource is the path relative to the API, e.g. "/items".
+	Resource string
+	// Prefix is the stage or base path which Public adds to Resource, e.g. "/prod" or "/v1". It is empty if there is
+	// none.
+	Prefix string
+	// Template is the API Gateway resource which matched the request, e.g. "/items/{id}". It is taken from
+	// Request.Resource, or RequestContext.ResourcePath if that is empty.
+	Template string
+}
+
+func newPathInfo(req *Request, basePath string) PathInfo {
+	info := PathInfo{
+		Public:   req.RequestContext.Path,
+		Resource: stripBasePath(req.Path, basePath),
+		Template: req.Resource,
+	}
+	if info.Public == "" {
+		info.Public = req.Path
+	}
+	if info.Template == "" {
+		info.Template = req.RequestContext.ResourcePath
+	}
+
+	switch {
+	case strings.HasSuffix(info.Public, info.Resource):
+		info.Prefix = strings.TrimSuffix(info.Public, info.Resource)
+	case info.Resource == "/":
+		// FYI: The root resource of a stage may be requested without a trailing slash, e.g. "/prod".
+		info.Prefix = info.Public
+	}
+	info.Prefix = strings.TrimSuffix(info.Prefix, "/")
+
+	return info
+}
+
+// path returns the path to use for mode.
+func (p PathInfo) path(mode PathMode) string {
+	if mode == PathModePublic {
+		return p.Public
+	}
+	return p.Resource
+}
+
+// stripBasePath removes basePath from the start of path if path is within basePath.
+func stripBasePath(path, basePath string) string {
+	basePath = "/" + strings.Trim(basePath, "/")
+	if basePath == "/" {
+		return path
+	}
+
+	switch {
+	case path == basePath:
+		return "/"
+	case strings.HasPrefix(path, basePath+"/"):
+		return path[len(basePath):]
+	default:
+		return path
+	}
+}
+
+// rewriteLocation adds prefix to a Location header which is an absolute path, e.g. "/items/1", so redirects issued by
+// handlers which only know the resource path reach the right stage or base path.
+func rewriteLocation(h http.Header, prefix string) {
+	loc := h.Get("Location")
+	if prefix == "" || !strings.HasPrefix(loc, "/") || strings.HasPrefix(loc, "//") {
+		return
+	}
+	h.Set("Location", prefix+loc)
+}
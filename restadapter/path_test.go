@@ -0,0 +1,128 @@
+package restadapter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPathInfo(t *testing.T) {
+	for name, tc := range map[string]struct {
+		path       string
+		publicPath string
+		basePath   string
+		want       PathInfo
+	}{
+		"ExecuteAPI": {
+			path:       "/items",
+			publicPath: "/prod/items",
+			want:       PathInfo{Public: "/prod/items", Resource: "/items", Prefix: "/prod"},
+		},
+		"ExecuteAPIRoot": {
+			path:       "/",
+			publicPath: "/prod",
+			want:       PathInfo{Public: "/prod", Resource: "/", Prefix: "/prod"},
+		},
+		"CustomDomain": {
+			path:       "/v1/items",
+			publicPath: "/v1/items",
+			want:       PathInfo{Public: "/v1/items", Resource: "/v1/items"},
+		},
+		"CustomDomainBasePath": {
+			path:       "/v1/items",
+			publicPath: "/v1/items",
+			basePath:   "v1",
+			want:       PathInfo{Public: "/v1/items", Resource: "/items", Prefix: "/v1"},
+		},
+		"CustomDomainBasePathRoot": {
+			path:       "/v1",
+			publicPath: "/v1",
+			basePath:   "/v1/",
+			want:       PathInfo{Public: "/v1", Resource: "/", Prefix: "/v1"},
+		},
+		"OutsideBasePath": {
+			path:       "/v10/items",
+			publicPath: "/prod/v10/items",
+			basePath:   "v1",
+			want:       PathInfo{Public: "/prod/v10/items", Resource: "/v10/items", Prefix: "/prod"},
+		},
+		"NoPublicPath": {
+			path: "/items",
+			want: PathInfo{Public: "/items", Resource: "/items"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := &Request{Path: tc.path, RequestContext: RequestContext{Path: tc.publicPath}}
+
+			assert.Equal(t, tc.want, newPathInfo(req, tc.basePath))
+		})
+	}
+}
+
+func TestHandler_Paths(t *testing.T) {
+	req := Request{
+		HTTPMethod: "GET",
+		Path:       "/items",
+		RequestContext: RequestContext{
+			DomainName: "id.execute-api.us-east-1.amazonaws.com",
+			Path:       "/prod/items",
+		},
+	}
+
+	var path string
+	var info PathInfo
+	mux := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		info, _ = PathInfoFrom(r.Context())
+		http.Redirect(w, r, "/items/1", http.StatusFound)
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		res, err := Handler(mux)(context.Background(), req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, "/items", path)
+		assert.Equal(t, PathInfo{Public: "/prod/items", Resource: "/items", Prefix: "/prod"}, info)
+		assert.Equal(t, []string{"/items/1"}, res.MultiValueHeaders["Location"])
+	})
+
+	t.Run("RewriteLocation", func(t *testing.T) {
+		res, err := Handler(mux, WithRewriteLocation(true))(context.Background(), req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, []string{"/prod/items/1"}, res.MultiValueHeaders["Location"])
+	})
+
+	t.Run("PublicPath", func(t *testing.T) {
+		_, err := Handler(mux, WithPathMode(PathModePublic))(context.Background(), req)
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, "/prod/items", path)
+	})
+}
+
+func TestRewriteLocation(t *testing.T) {
+	for loc, want := range map[string]string{
+		"/items/1":                 "/prod/items/1",
+		"items/1":                  "items/1",
+		"//example.com/items":      "//example.com/items",
+		"https://example.com/item": "https://example.com/item",
+		"":                         "",
+	} {
+		h := http.Header{}
+		if loc != "" {
+			h.Set("Location", loc)
+		}
+		rewriteLocation(h, "/prod")
+
+		assert.Equal(t, want, h.Get("Location"), loc)
+	}
+}
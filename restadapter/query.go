@@ -0,0 +1,228 @@
+package restadapter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// QueryMode configures how TransformRequest reconstructs the raw query string from MultiValueQueryStringParameters.
+//
+// REST APIs only provide the decoded query string parameters so the query string sent by the client cannot always be
+// reconstructed exactly. API Gateway has already lost:
+//   - the original percent-encoding, e.g. whether a space was sent as "+" or "%20" or whether "~" was escaped,
+//   - the interleaving of repeated keys, e.g. "a=1&b=2&a=3" arrives as {"a": ["1", "3"], "b": ["2"]},
+//   - the difference between "flag" and "flag=".
+type QueryMode int
+
+const (
+	// QueryModeCanonical encodes the parameters using url.Values.Encode, which sorts them by key and escapes them
+	// using url.QueryEscape. This is the default.
+	QueryModeCanonical QueryMode = iota
+	// QueryModePreserve keeps the keys in the order of the multiValueQueryStringParameters object of the event, or of
+	// the query string for Requests built by NewRequestFromHTTP, and only escapes characters which are not allowed in
+	// a query or would change its meaning. Spaces are escaped as "%20". Use this mode for signature checks over the
+	// query string, e.g. for webhooks or signed URLs, bearing in mind what API Gateway has already lost.
+	//
+	// The key order depends on the order of the JSON object, which AWS does not guarantee to match the order sent by
+	// the client. Verify it for your API before relying on it.
+	QueryModePreserve
+)
+
+// UnmarshalJSON implements json.Unmarshaler. It records the order of the MultiValueQueryStringParameters keys for
+// QueryModePreserve and MarshalJSON. The order is kept in an unexported field, so a decoded Request does not compare
+// equal to a Request literal with the same fields.
+func (r *Request) UnmarshalJSON(b []byte) error {
+	type request Request
+	aux := struct {
+		*request
+		MultiValueQueryStringParameters json.RawMessage `json:"multiValueQueryStringParameters"`
+	}{
+		request: (*request)(r),
+	}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	params, keys, err := decodeQueryParameters(aux.MultiValueQueryStringParameters)
+	if err != nil {
+		return fmt.Errorf("failed to decode multiValueQueryStringParameters: %v", err)
+	}
+	r.MultiValueQueryStringParameters = params
+	r.setQueryKeys(keys)
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. MultiValueQueryStringParameters are written in the order recorded by
+// UnmarshalJSON or NewRequestFromHTTP, so the order survives a round trip, e.g. through localgw.
+func (r Request) MarshalJSON() ([]byte, error) {
+	type request Request
+	if r.queryKeys == nil {
+		return json.Marshal(request(r))
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range r.orderedQueryKeys() {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		values, err := json.Marshal(r.MultiValueQueryStringParameters[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(values)
+	}
+	buf.WriteByte('}')
+
+	return json.Marshal(struct {
+		request
+		MultiValueQueryStringParameters json.RawMessage `json:"multiValueQueryStringParameters"`
+	}{
+		request:                         request(r),
+		MultiValueQueryStringParameters: buf.Bytes(),
+	})
+}
+
+// decodeQueryParameters decodes the JSON object b and returns its keys in order. It returns nil if b is empty or null.
+func decodeQueryParameters(b json.RawMessage) (map[string][]string, []string, error) {
+	if len(b) == 0 || string(b) == "null" {
+		return nil, nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if tok, err := dec.Token(); err != nil {
+		return nil, nil, err
+	} else if tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("unexpected %v", tok)
+	}
+
+	params := map[string][]string{}
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		k := tok.(string)
+
+		var values []string
+		if err := dec.Decode(&values); err != nil {
+			return nil, nil, err
+		}
+		if _, ok := params[k]; !ok {
+			keys = append(keys, k)
+		}
+		params[k] = values
+	}
+	return params, keys, nil
+}
+
+// setQueryKeys records the order of the MultiValueQueryStringParameters keys.
+func (r *Request) setQueryKeys(keys []string) {
+	r.queryKeys = keys
+}
+
+// orderedQueryKeys returns the MultiValueQueryStringParameters keys in the recorded order. Keys which were not
+// recorded, e.g. because they were added after unmarshalling, follow in sorted order.
+func (r *Request) orderedQueryKeys() []string {
+	seen := map[string]bool{}
+	var keys, rest []string
+	for _, k := range r.queryKeys {
+		if _, ok := r.MultiValueQueryStringParameters[k]; ok && !seen[k] {
+			seen[k] = true
+			keys = append(keys, k)
+		}
+	}
+	for k := range r.MultiValueQueryStringParameters {
+		if !seen[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+// rawQuery returns the query string of r for mode.
+func (r *Request) rawQuery(mode QueryMode) string {
+	if mode != QueryModePreserve {
+		qValues := url.Values{}
+		for k, parts := range r.MultiValueQueryStringParameters {
+			for _, part := range parts {
+				qValues.Add(k, part)
+			}
+		}
+		return qValues.Encode()
+	}
+
+	var sb strings.Builder
+	for _, k := range r.orderedQueryKeys() {
+		for _, v := range r.MultiValueQueryStringParameters[k] {
+			if sb.Len() > 0 {
+				sb.WriteByte('&')
+			}
+			sb.WriteString(queryEscape(k))
+			sb.WriteByte('=')
+			sb.WriteString(queryEscape(v))
+		}
+	}
+	return sb.String()
+}
+
+// queryKeyOrder returns the keys of rawQuery in the order they first appear, skipping keys which url.ParseQuery skips.
+func queryKeyOrder(rawQuery string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, part := range strings.Split(rawQuery, "&") {
+		if part == "" || strings.Contains(part, ";") {
+			continue
+		}
+		k, _, _ := strings.Cut(part, "=")
+		k, err := url.QueryUnescape(k)
+		if err != nil || seen[k] {
+			continue
+		}
+		seen[k] = true
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// queryEscape escapes the characters of s which are not allowed in a query component by RFC 3986, as well as "&",
+// "=", "+", ";" and "#" which would change the meaning of the query.
+func queryEscape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if shouldEscapeQuery(c) {
+			fmt.Fprintf(&sb, "%%%02X", c)
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String()
+}
+
+func shouldEscapeQuery(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return false
+	}
+	switch c {
+	case '-', '.', '_', '~', // unreserved
+		'!', '$', '\'', '(', ')', '*', ',', // sub-delims, except "&", "+", ";" and "="
+		':', '@', '/', '?':
+		return false
+	}
+	return true
+}
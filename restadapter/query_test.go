@@ -0,0 +1,130 @@
+package restadapter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformRequest_QueryMode(t *testing.T) {
+	var req Request
+	err := json.Unmarshal([]byte(`{
+		"httpMethod": "GET",
+		"path": "/webhook",
+		"multiValueQueryStringParameters": {
+			"timestamp": ["1700000000"],
+			"b": ["2", "1"],
+			"a": ["x y", "1+1=2&c", "~:/?@"]
+		},
+		"requestContext": {"domainName": "example.com"}
+	}`), &req)
+	if !assert.NoError(t, err, "failed to unmarshal request") {
+		return
+	}
+
+	t.Run("Canonical", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "a=x+y&a=1%2B1%3D2%26c&a=~%3A%2F%3F%40&b=2&b=1&timestamp=1700000000", httpReq.URL.RawQuery)
+	})
+
+	t.Run("Preserve", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req, WithQueryMode(QueryModePreserve))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "timestamp=1700000000&b=2&b=1&a=x%20y&a=1%2B1%3D2%26c&a=~:/?@", httpReq.URL.RawQuery)
+		assert.Equal(t, []string{"x y", "1+1=2&c", "~:/?@"}, httpReq.URL.Query()["a"])
+	})
+
+	t.Run("PreserveUnmarshalled", func(t *testing.T) {
+		// FYI: Keys added after unmarshalling are appended in sorted order.
+		req := req
+		req.MultiValueQueryStringParameters = map[string][]string{
+			"timestamp": {"1700000000"},
+			"d":         {"4"},
+			"c":         {"3"},
+		}
+
+		httpReq, err := TransformRequest(context.Background(), &req, WithQueryMode(QueryModePreserve))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "timestamp=1700000000&c=3&d=4", httpReq.URL.RawQuery)
+	})
+
+	t.Run("Null", func(t *testing.T) {
+		var req Request
+		err := json.Unmarshal([]byte(`{"httpMethod": "GET", "path": "/", "multiValueQueryStringParameters": null}`), &req)
+		if !assert.NoError(t, err, "failed to unmarshal request") {
+			return
+		}
+
+		httpReq, err := TransformRequest(context.Background(), &req, WithQueryMode(QueryModePreserve))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "", httpReq.URL.RawQuery)
+	})
+}
+
+func TestRequest_QueryKeyOrder(t *testing.T) {
+	t.Run("MarshalRoundTrip", func(t *testing.T) {
+		var req Request
+		err := json.Unmarshal([]byte(`{"multiValueQueryStringParameters": {"b": ["2"], "a": ["1"]}}`), &req)
+		if !assert.NoError(t, err, "failed to unmarshal request") {
+			return
+		}
+
+		b, err := json.Marshal(req)
+		if !assert.NoError(t, err, "failed to marshal request") {
+			return
+		}
+		assert.Contains(t, string(b), `"multiValueQueryStringParameters":{"b":["2"],"a":["1"]}`)
+
+		var rtReq Request
+		if !assert.NoError(t, json.Unmarshal(b, &rtReq), "failed to unmarshal request") {
+			return
+		}
+		assert.Equal(t, "b=2&a=1", rtReq.rawQuery(QueryModePreserve))
+	})
+
+	t.Run("NewRequestFromHTTP", func(t *testing.T) {
+		req, err := NewRequestFromHTTP(httptest.NewRequest("GET", "/?z=1&a=2&z=3&bad;key=4", nil))
+		if !assert.NoError(t, err, "failed to create request") {
+			return
+		}
+
+		assert.Equal(t, "z=1&z=3&a=2", req.rawQuery(QueryModePreserve))
+	})
+
+	t.Run("Sorted", func(t *testing.T) {
+		var req Request
+		err := json.Unmarshal([]byte(`{"path": "/", "multiValueQueryStringParameters": {"a": ["1"], "b": ["2"]}}`), &req)
+		if !assert.NoError(t, err, "failed to unmarshal request") {
+			return
+		}
+
+		assert.Equal(t, []string{"a", "b"}, req.queryKeys)
+		assertRequestEqual(t, &Request{
+			Path:                            "/",
+			MultiValueQueryStringParameters: map[string][]string{"a": {"1"}, "b": {"2"}},
+		}, &req)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		var req Request
+		err := json.Unmarshal([]byte(`{"multiValueQueryStringParameters": ["a"]}`), &req)
+
+		assert.EqualError(t, err, "failed to decode multiValueQueryStringParameters: unexpected [")
+	})
+}
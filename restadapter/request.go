@@ -1,85 +1,283 @@
 package restadapter
 
 import (
-	"bytes"
 	"context"
 	"encoding/base64"
 	"fmt"
-	"io"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"unicode/utf8"
+
+	"harrisonhjones.com/go-apigw-http-adapter/internal/request"
 )
 
+// ErrInvalidClientCert is wrapped by the error returned by TransformRequest if the mutual TLS client certificate of
+// the request cannot be parsed. See WithClientCertErrorHandler.
+var ErrInvalidClientCert = request.ErrInvalidClientCert
+
 // Request contains all relevant API Gateway REST API request data needed to transform it into a http.Request.
 // MultiValueHeaders are supported by REST APIs and always contain all Headers so Headers can be safely ignored.
 // MultiValueQueryStringParameters are supported by REST APIs and always contain all QueryStringParameters so
 // QueryStringParameters can be safely ignored.
 // https://docs.aws.amazon.com/apigateway/latest/developerguide/set-up-lambda-proxy-integrations.html
 type Request struct {
-	Path                            string              `json:"path"` // The url path for the caller
+	Resource                        string              `json:"resource,omitempty"` // e.g. "/items/{id}"
+	Path                            string              `json:"path"`               // The url path for the caller
 	HTTPMethod                      string              `json:"httpMethod"`
+	Headers                         map[string]string   `json:"headers"`
 	MultiValueHeaders               map[string][]string `json:"multiValueHeaders"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters"`
 	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters"`
+	PathParameters                  map[string]string   `json:"pathParameters,omitempty"`
+	StageVariables                  map[string]string   `json:"stageVariables,omitempty"`
 	RequestContext                  RequestContext      `json:"requestContext"`
 	Body                            string              `json:"body"`
 	IsBase64Encoded                 bool                `json:"isBase64Encoded,omitempty"`
+
+	// queryKeys are the MultiValueQueryStringParameters keys in the order they were received.
+	// See QueryModePreserve.
+	queryKeys []string
 }
 
-// RequestContext contains all relevant data needed for Request transformation.
+// RequestContext contains the API Gateway metadata of the Request. It is stored in the context of the transformed
+// http.Request and can be retrieved using RequestContextFrom.
+// https://docs.aws.amazon.com/apigateway/latest/developerguide/set-up-lambda-proxy-integrations.html#api-gateway-simple-proxy-for-lambda-input-format
 type RequestContext struct {
-	DomainName string `json:"domainName"`
+	AccountID         string                    `json:"accountId,omitempty"`
+	APIID             string                    `json:"apiId,omitempty"`
+	Authorizer        *RequestContextAuthorizer `json:"authorizer,omitempty"`
+	DomainName        string                    `json:"domainName"`
+	DomainPrefix      string                    `json:"domainPrefix,omitempty"`
+	ExtendedRequestID string                    `json:"extendedRequestId,omitempty"`
+	HTTPMethod        string                    `json:"httpMethod,omitempty"`
+	Identity          RequestContextIdentity    `json:"identity"`
+	// Path is the path requested by the client, including the stage or base path mapping if any.
+	Path        string `json:"path,omitempty"`
+	Protocol    string `json:"protocol,omitempty"`
+	RequestID   string `json:"requestId,omitempty"`
+	RequestTime string `json:"requestTime,omitempty"`
+	// RequestTimeEpoch is the request time in milliseconds since the Unix epoch.
+	RequestTimeEpoch int64  `json:"requestTimeEpoch,omitempty"`
+	ResourceID       string `json:"resourceId,omitempty"`
+	// ResourcePath is the path template of the API resource, e.g. "/pets/{id}".
+	ResourcePath string `json:"resourcePath,omitempty"`
+	Stage        string `json:"stage,omitempty"`
+}
+
+// RequestTimeLayout is the layout of RequestContext.RequestTime for use with time.Parse.
+const RequestTimeLayout = "02/Jan/2006:15:04:05 -0700"
+
+// RequestContextIdentity contains the identity of the caller. Most fields are only set for certain authorization
+// types, e.g. the Cognito fields for AWS_IAM authorization using Amazon Cognito credentials.
+type RequestContextIdentity struct {
+	AccessKey                     string      `json:"accessKey,omitempty"`
+	AccountID                     string      `json:"accountId,omitempty"`
+	APIKey                        string      `json:"apiKey,omitempty"`
+	APIKeyID                      string      `json:"apiKeyId,omitempty"`
+	Caller                        string      `json:"caller,omitempty"`
+	ClientCert                    *ClientCert `json:"clientCert,omitempty"`
+	CognitoAuthenticationProvider string      `json:"cognitoAuthenticationProvider,omitempty"`
+	CognitoAuthenticationType     string      `json:"cognitoAuthenticationType,omitempty"`
+	CognitoIdentityID             string      `json:"cognitoIdentityId,omitempty"`
+	CognitoIdentityPoolID         string      `json:"cognitoIdentityPoolId,omitempty"`
+	PrincipalOrgID                string      `json:"principalOrgId,omitempty"`
+	SourceIP                      string      `json:"sourceIp"`
+	User                          string      `json:"user,omitempty"`
+	UserAgent                     string      `json:"userAgent,omitempty"`
+	UserARN                       string      `json:"userArn,omitempty"`
+}
+
+// ClientCert contains the client certificate presented by the caller when mutual TLS is enabled. TransformRequest
+// parses ClientCertPEM into the PeerCertificates of the http.Request TLS state.
+type ClientCert struct {
+	ClientCertPEM string             `json:"clientCertPem"`
+	SubjectDN     string             `json:"subjectDN"`
+	IssuerDN      string             `json:"issuerDN"`
+	SerialNumber  string             `json:"serialNumber"`
+	Validity      ClientCertValidity `json:"validity"`
+}
+
+// ClientCertValidity contains the validity period of a ClientCert, e.g. "May 28 12:30:02 2019 GMT".
+type ClientCertValidity struct {
+	NotBefore string `json:"notBefore"`
+	NotAfter  string `json:"notAfter"`
 }
 
 // TransformRequest transforms a *Request to a *http.Request.
 // A non-nil error will be returned if the *Request is nil or if the transformation fails.
 // The *Request will not be mutated during transformation.
-func TransformRequest(ctx context.Context, req *Request) (*http.Request, error) {
+//
+// The URL path is the resource path, see PathInfo, unless WithPathMode or WithBasePath are passed. The URL scheme and
+// Host are taken from the X-Forwarded-Proto, Host and X-Forwarded-Port headers unless WithScheme or WithHost are
+// passed. Proto is taken from the request context and TLS is set for https requests, including the PeerCertificates of
+// mutual TLS requests. RemoteAddr is set to the source IP of the request with a placeholder port of 0, or taken from
+// X-Forwarded-For if WithForwardedFor is passed. The query string is rebuilt from MultiValueQueryStringParameters, see
+// QueryMode and WithQueryMode. PathParameters are set as path values, see http.Request.PathValue.
+//
+// The RequestContext, PathInfo and stage variables are stored in the context of the *http.Request, see
+// RequestContextFrom, PathInfoFrom and StageVariablesFrom.
+func TransformRequest(ctx context.Context, req *Request, opts ...RequestOption) (*http.Request, error) {
+	return transformRequest(ctx, req, newRequestOptions(opts))
+}
+
+func transformRequest(ctx context.Context, req *Request, o *requestOptions) (*http.Request, error) {
 	if req == nil {
 		return nil, fmt.Errorf("req cannot be nil")
 	}
-	// Mirror how http.Request bodies normally behave.
-	// From the docs:
-	// For server requests, the Request Body is always non-nil
-	// but will return EOF immediately when no body is present.
-	var body io.Reader
-	if req.IsBase64Encoded {
-		b, err := base64.StdEncoding.DecodeString(req.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode body: %v", err)
-		}
-		body = bytes.NewBuffer(b)
-	} else {
-		body = strings.NewReader(req.Body)
-	}
-
-	u, err := url.Parse("https://" + req.RequestContext.DomainName + req.Path)
+	body, err := request.Body(req.Body, req.IsBase64Encoded)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse url: %v", err)
+		return nil, err
 	}
 
-	qValues := u.Query()
-	for k, parts := range req.MultiValueQueryStringParameters {
-		for _, part := range parts {
-			qValues.Add(k, part)
-		}
+	info := newPathInfo(req, o.basePath)
+
+	// FYI: REST API paths are still percent-encoded so the decoded path is reconstructed from them.
+	path, rawPath := request.URLPath("", info.path(o.pathMode))
+	u := &url.URL{
+		Scheme:  "https",
+		Host:    req.RequestContext.DomainName,
+		Path:    path,
+		RawPath: rawPath,
 	}
-	u.RawQuery = qValues.Encode()
+
+	u.RawQuery = req.rawQuery(o.queryMode)
 
 	hReq, err := http.NewRequest(req.HTTPMethod, u.String(), body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new http request: %v", err)
 	}
 
+	ctx = withRequestContext(ctx, req.RequestContext)
+	ctx = withPathInfo(ctx, info)
+	ctx = withStageVariables(ctx, req.StageVariables)
 	hReq = hReq.WithContext(ctx)
 
-	// Q: Why not just `hReq.Header = req.MultiValueHeaders` here?
-	// A: `Header.Add` canonicalizes the header key.
-	for k, vals := range req.MultiValueHeaders {
-		for _, val := range vals {
-			hReq.Header.Add(k, val)
+	request.AddMultiValueHeaders(hReq.Header, req.MultiValueHeaders)
+
+	request.SetServerFields(hReq, req.RequestContext.DomainName, req.RequestContext.Protocol, o.scheme, o.host)
+	if cert := req.RequestContext.Identity.ClientCert; cert != nil {
+		if err := request.SetPeerCertificates(hReq, cert.ClientCertPEM); err != nil {
+			if err := o.clientCertErrorHandler(err); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	request.SetPathValues(hReq, req.PathParameters)
+
+	hReq.RemoteAddr = request.RemoteAddr(req.RequestContext.Identity.SourceIP)
+	if o.trustedProxies >= 0 {
+		if ip, ok := request.ForwardedFor(hReq.Header, o.trustedProxies); ok {
+			hReq.RemoteAddr = request.RemoteAddr(ip)
 		}
 	}
 
 	return hReq, nil
 }
+
+// NewRequestFromHTTP builds the *Request API Gateway would send for the *http.Request, e.g. for tests or to replay
+// requests. It is the inverse of TransformRequest. The *http.Request body is read and closed.
+//
+// As with API Gateway, Headers and QueryStringParameters contain the last value of each header and parameter.
+// Bodies which are not valid UTF-8 are base64 encoded. The source IP is taken from r.RemoteAddr and X-Forwarded-Proto
+// is added if missing. Fields which cannot be derived from r, e.g. the stage, resource and request id, are left empty.
+func NewRequestFromHTTP(r *http.Request) (*Request, error) {
+	if r == nil {
+		return nil, fmt.Errorf("r cannot be nil")
+	}
+
+	host := r.Host
+	if host == "" {
+		host = r.URL.Host
+	}
+
+	req := &Request{
+		Path:       r.URL.EscapedPath(),
+		HTTPMethod: r.Method,
+		RequestContext: RequestContext{
+			DomainName:   host,
+			DomainPrefix: domainPrefix(host),
+			HTTPMethod:   r.Method,
+			Identity: RequestContextIdentity{
+				SourceIP:  sourceIP(r.RemoteAddr),
+				UserAgent: r.UserAgent(),
+			},
+			Path:     r.URL.EscapedPath(),
+			Protocol: r.Proto,
+		},
+	}
+
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if host != "" {
+		header.Set("Host", host)
+	}
+	if header.Get("X-Forwarded-Proto") == "" {
+		header.Set("X-Forwarded-Proto", scheme(r))
+	}
+	if len(header) > 0 {
+		req.Headers = map[string]string{}
+		req.MultiValueHeaders = map[string][]string{}
+		for k, v := range header {
+			req.Headers[k] = v[len(v)-1]
+			req.MultiValueHeaders[k] = v
+		}
+	}
+
+	query := r.URL.Query()
+	if len(query) > 0 {
+		req.QueryStringParameters = map[string]string{}
+		req.MultiValueQueryStringParameters = map[string][]string{}
+		for k, v := range query {
+			req.QueryStringParameters[k] = v[len(v)-1]
+			req.MultiValueQueryStringParameters[k] = v
+		}
+		req.setQueryKeys(queryKeyOrder(r.URL.RawQuery))
+	}
+
+	if r.Body != nil {
+		defer r.Body.Close()
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read body: %v", err)
+		}
+		if utf8.Valid(body) {
+			req.Body = string(body)
+		} else {
+			req.Body = base64.StdEncoding.EncodeToString(body)
+			req.IsBase64Encoded = true
+		}
+	}
+
+	return req, nil
+}
+
+// scheme returns the scheme r was received with.
+func scheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// domainPrefix returns the first label of host, e.g. "id" for "id.execute-api.us-east-1.amazonaws.com".
+func domainPrefix(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	prefix, _, _ := strings.Cut(host, ".")
+	return prefix
+}
+
+// sourceIP returns the IP of remoteAddr, which may or may not include a port.
+func sourceIP(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
@@ -1,9 +1,13 @@
 package restadapter
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -42,12 +46,14 @@ func TestTransformRequest_HappyPath(t *testing.T) {
 			return
 		}
 
-		assert.Equal(t, tstCtx, httpReq.Context())
+		reqCtx, ok := RequestContextFrom(httpReq.Context())
+		assert.True(t, ok, "missing request context")
+		assert.Equal(t, req.RequestContext, reqCtx)
 
 		assert.Equal(t,
 			http.Header{
 				"Cookie":       []string{"cookie1=val1; cookie2=val2"},
-				"Header-Three": []string{"value1", "value2", "value3", "value4"},
+				"Header-Three": []string{"value4", "value3", "value1", "value2"}, // FYI: Sorted by key.
 				"Header1":      []string{"value1"},
 				"Header2":      []string{"value1", "value2"},
 			},
@@ -106,3 +112,258 @@ func TestTransformRequest_HappyPath(t *testing.T) {
 		assert.EqualError(t, err, "failed to decode body: illegal base64 data at input byte 4")
 	})
 }
+
+// assertRequestEqual is like assert.Equal but ignores the recorded query key order of actual.
+func assertRequestEqual(t *testing.T, expected, actual *Request) bool {
+	t.Helper()
+
+	if actual != nil {
+		r := *actual
+		r.queryKeys = nil
+		actual = &r
+	}
+	return assert.Equal(t, expected, actual)
+}
+
+func TestNewRequestFromHTTP(t *testing.T) {
+	t.Run("Text", func(t *testing.T) {
+		httpReq, err := http.NewRequest("POST", "https://example.com/my/path?parameter1=value1&parameter1=value2&parameter2=value", strings.NewReader("Hello World!"))
+		if !assert.NoError(t, err, "failed to create request") {
+			return
+		}
+		httpReq.Header.Add("Header1", "value1")
+		httpReq.Header.Add("Header2", "value1")
+		httpReq.Header.Add("Header2", "value2")
+
+		req, err := NewRequestFromHTTP(httpReq)
+		if !assert.NoError(t, err, "failed to create request") {
+			return
+		}
+
+		assertRequestEqual(t,
+			&Request{
+				Path:       "/my/path",
+				HTTPMethod: "POST",
+				Headers: map[string]string{
+					"Header1":           "value1",
+					"Header2":           "value2",
+					"Host":              "example.com",
+					"X-Forwarded-Proto": "http", // FYI: httpReq was not received over TLS.
+				},
+				MultiValueHeaders: map[string][]string{
+					"Header1":           {"value1"},
+					"Header2":           {"value1", "value2"},
+					"Host":              {"example.com"},
+					"X-Forwarded-Proto": {"http"},
+				},
+				QueryStringParameters: map[string]string{
+					"parameter1": "value2",
+					"parameter2": "value",
+				},
+				MultiValueQueryStringParameters: map[string][]string{
+					"parameter1": {"value1", "value2"},
+					"parameter2": {"value"},
+				},
+				RequestContext: RequestContext{
+					DomainName:   "example.com",
+					DomainPrefix: "example",
+					HTTPMethod:   "POST",
+					Path:         "/my/path",
+					Protocol:     "HTTP/1.1",
+				},
+				Body:            "Hello World!",
+				IsBase64Encoded: false,
+			},
+			req)
+
+		// FYI: Round trip the request.
+		rtReq, err := TransformRequest(context.Background(), req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+		assert.Equal(t, "http://example.com/my/path?parameter1=value1&parameter1=value2&parameter2=value", rtReq.URL.String())
+		assert.Equal(t, []string{"value1", "value2"}, rtReq.Header["Header2"])
+	})
+
+	t.Run("Binary", func(t *testing.T) {
+		httpReq := httptest.NewRequest("PUT", "/upload", bytes.NewReader([]byte{0xff, 0x00, 0x01}))
+
+		req, err := NewRequestFromHTTP(httpReq)
+		if !assert.NoError(t, err, "failed to create request") {
+			return
+		}
+
+		assert.Equal(t, "example.com", req.RequestContext.DomainName)      // FYI: httptest.NewRequest uses example.com.
+		assert.Equal(t, "192.0.2.1", req.RequestContext.Identity.SourceIP) // FYI: httptest.NewRequest uses 192.0.2.1:1234.
+		assert.Equal(t, "/wAB", req.Body)
+		assert.True(t, req.IsBase64Encoded)
+		assert.Nil(t, req.MultiValueQueryStringParameters)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		_, err := NewRequestFromHTTP(nil)
+
+		assert.EqualError(t, err, "r cannot be nil")
+	})
+}
+
+func TestTransformRequest_RemoteAddr(t *testing.T) {
+	req := Request{
+		HTTPMethod: "GET",
+		Path:       "/",
+		MultiValueHeaders: map[string][]string{
+			"X-Forwarded-For": {"198.51.100.1, 203.0.113.1"},
+		},
+		RequestContext: RequestContext{
+			DomainName: "example.com",
+			Identity: RequestContextIdentity{
+				SourceIP: "2001:db8::1",
+			},
+		},
+	}
+
+	t.Run("SourceIP", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "[2001:db8::1]:0", httpReq.RemoteAddr)
+	})
+
+	t.Run("ForwardedFor", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req, WithForwardedFor(1))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "198.51.100.1:0", httpReq.RemoteAddr)
+	})
+}
+
+func TestTransformRequest_ServerFields(t *testing.T) {
+	req := Request{
+		HTTPMethod: "GET",
+		Path:       "/my/path",
+		MultiValueHeaders: map[string][]string{
+			"Host":              {"api.example.com"},
+			"X-Forwarded-Port":  {"8443"},
+			"X-Forwarded-Proto": {"https"},
+		},
+		RequestContext: RequestContext{
+			DomainName: "id.execute-api.us-east-1.amazonaws.com",
+			Protocol:   "HTTP/1.1",
+		},
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "https://api.example.com:8443/my/path", httpReq.URL.String())
+		assert.Equal(t, "api.example.com:8443", httpReq.Host)
+		assert.Equal(t, "HTTP/1.1", httpReq.Proto)
+		assert.Equal(t, "/my/path", httpReq.RequestURI)
+		assert.NotNil(t, httpReq.TLS)
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &req, WithScheme("http"), WithHost("localhost"))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "http://localhost/my/path", httpReq.URL.String())
+		assert.Nil(t, httpReq.TLS)
+	})
+}
+
+func TestTransformRequest_ClientCert(t *testing.T) {
+	newReq := func(pem string) *Request {
+		return &Request{
+			HTTPMethod: "GET",
+			Path:       "/",
+			RequestContext: RequestContext{
+				DomainName: "example.com",
+				Identity: RequestContextIdentity{
+					ClientCert: &ClientCert{ClientCertPEM: pem},
+				},
+			},
+		}
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), newReq(readFile(t, clientCertFile)))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		if assert.NotNil(t, httpReq.TLS) && assert.Len(t, httpReq.TLS.PeerCertificates, 1) {
+			assert.Equal(t, "client.example.com", httpReq.TLS.PeerCertificates[0].Subject.CommonName)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := TransformRequest(context.Background(), newReq("CERT_CONTENT"))
+
+		assert.True(t, errors.Is(err, ErrInvalidClientCert))
+	})
+
+	t.Run("InvalidIgnored", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), newReq("CERT_CONTENT"), WithClientCertErrorHandler(func(err error) error {
+			return nil
+		}))
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		if assert.NotNil(t, httpReq.TLS) {
+			assert.Empty(t, httpReq.TLS.PeerCertificates)
+		}
+	})
+}
+
+func TestTransformRequest_RawPath(t *testing.T) {
+	// FYI: The encodings themselves are tested by internal/request.URLPath.
+	raw, decoded := "/files/a%2Fb.txt", "/files/a/b.txt"
+
+	t.Run("Encoded", func(t *testing.T) {
+		httpReq, err := TransformRequest(context.Background(), &Request{
+			HTTPMethod:     "GET",
+			Path:           raw,
+			RequestContext: RequestContext{DomainName: "example.com"},
+		})
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, decoded, httpReq.URL.Path)
+		assert.Equal(t, raw, httpReq.URL.EscapedPath())
+		assert.Equal(t, raw, httpReq.RequestURI)
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		req, err := NewRequestFromHTTP(httptest.NewRequest("GET", raw, nil))
+		if !assert.NoError(t, err, "failed to create request") {
+			return
+		}
+
+		httpReq, err := TransformRequest(context.Background(), req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, raw, httpReq.URL.EscapedPath())
+	})
+}
+
+func TestTransformRequest_PathParameters(t *testing.T) {
+	httpReq, err := TransformRequest(context.Background(), loadRequest(t, "restapi-get.json"))
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	assert.Equal(t, "path", httpReq.PathValue("proxy"))
+}
@@ -1,10 +1,14 @@
 package restadapter
 
 import (
+	"bytes"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+
+	"harrisonhjones.com/go-apigw-http-adapter/internal/response"
 )
 
 // Response configures the response to be returned by the API Gateway REST API for the request.
@@ -17,8 +21,30 @@ type Response struct {
 	IsBase64Encoded   bool                `json:"isBase64Encoded,omitempty"`
 }
 
+// UTF8Mode configures how response bodies which are not valid UTF-8, and would otherwise not be base64 encoded, are
+// handled. Such bodies cannot be sent as-is as JSON strings cannot contain invalid UTF-8.
+type UTF8Mode = response.UTF8Mode
+
+const (
+	// UTF8Auto base64 encodes response bodies which are not valid UTF-8. This is the default.
+	UTF8Auto = response.UTF8Auto
+	// UTF8Strict rejects response bodies which are not valid UTF-8 with an *InvalidUTF8Error.
+	UTF8Strict = response.UTF8Strict
+)
+
+// InvalidUTF8Error is returned by TransformResponse in UTF8Strict mode. Its Offset is the index of the first invalid
+// byte of the body.
+type InvalidUTF8Error = response.InvalidUTF8Error
+
 // TransformResponse transforms an http.Response to a Response.
-func TransformResponse(res *http.Response, encRes func(*http.Response) bool) (*Response, error) {
+// The body is base64 encoded if encRes reports true for res, e.g. DefaultEncodingPolicy. A nil encRes never encodes.
+// A body which is not valid UTF-8 is base64 encoded regardless of encRes, unless WithUTF8Mode(UTF8Strict) is given, in
+// which case an *InvalidUTF8Error is returned.
+func TransformResponse(res *http.Response, encRes EncodingPolicy, opts ...ResponseOption) (*Response, error) {
+	return transformResponse(res, encRes, newResponseOptions(opts))
+}
+
+func transformResponse(res *http.Response, encRes EncodingPolicy, o *responseOptions) (*Response, error) {
 	apigwRes := &Response{
 		StatusCode:        res.StatusCode,
 		MultiValueHeaders: map[string][]string{},
@@ -29,15 +55,46 @@ func TransformResponse(res *http.Response, encRes func(*http.Response) bool) (*R
 		return nil, fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	if encRes != nil && encRes(res) {
-		apigwRes.Body = base64.StdEncoding.EncodeToString(body)
-		apigwRes.IsBase64Encoded = true
-	} else {
-		apigwRes.Body = string(body)
-		apigwRes.IsBase64Encoded = false
+	apigwRes.Body, apigwRes.IsBase64Encoded, err = response.EncodeBody(body, encRes != nil && encRes(res), o.utf8Mode)
+	if err != nil {
+		return nil, err
 	}
 
 	apigwRes.MultiValueHeaders = res.Header
 
 	return apigwRes, nil
 }
+
+// ToHTTP transforms the Response into an *http.Response as it would be received by a client. It is the inverse of
+// TransformResponse. The body is base64 decoded if needed and every value of MultiValueHeaders is added.
+func (r *Response) ToHTTP() (*http.Response, error) {
+	body := []byte(r.Body)
+	if r.IsBase64Encoded {
+		b, err := base64.StdEncoding.DecodeString(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode body: %v", err)
+		}
+		body = b
+	}
+
+	res := &http.Response{
+		Status:        strconv.Itoa(r.StatusCode) + " " + http.StatusText(r.StatusCode),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+
+	// Q: Why not just `res.Header = r.MultiValueHeaders` here?
+	// A: `Header.Add` canonicalizes the header key.
+	for k, vals := range r.MultiValueHeaders {
+		for _, v := range vals {
+			res.Header.Add(k, v)
+		}
+	}
+
+	return res, nil
+}
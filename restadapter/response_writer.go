@@ -0,0 +1,24 @@
+package restadapter
+
+import "harrisonhjones.com/go-apigw-http-adapter/internal/response"
+
+// DefaultMaxBodySize is the default maximum response body size used by Handler. It matches the Lambda synchronous
+// invocation payload limit. Note that base64 encoding grows the body by a third so encoded bodies should be smaller.
+const DefaultMaxBodySize = 6 * 1024 * 1024
+
+// Errors returned by ResponseWriter.
+var (
+	ErrBodyTooLarge       = response.ErrBodyTooLarge
+	ErrClosed             = response.ErrClosed
+	ErrHijackNotSupported = response.ErrHijackNotSupported
+)
+
+// ResponseWriter buffers the response of the http.Handler served by Handler. Pass its Result to TransformResponse to
+// build the REST API Response.
+type ResponseWriter = response.Writer
+
+// NewResponseWriter returns a ResponseWriter which accepts at most maxBodySize bytes of body, e.g. DefaultMaxBodySize.
+// A maxBodySize <= 0 means no limit.
+func NewResponseWriter(maxBodySize int) *ResponseWriter {
+	return response.NewWriter(maxBodySize)
+}
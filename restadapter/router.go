@@ -0,0 +1,73 @@
+package restadapter
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Router is an http.Handler which dispatches transformed requests on the API Gateway resource which matched them,
+// e.g. "/items/{id}", see PathInfo.Template, rather than matching the path again. Requests to resources without a
+// handler, e.g. "/{proxy+}", and requests which were not transformed by TransformRequest are passed to the fallback
+// handler.
+//
+// Path parameters of the resource are available via http.Request.PathValue.
+type Router struct {
+	mu       sync.RWMutex
+	routes   map[string]http.Handler
+	fallback http.Handler
+}
+
+// NewRouter returns a Router which passes requests to unknown resources to fallback. A nil fallback responds with
+// 404 Not Found.
+func NewRouter(fallback http.Handler) *Router {
+	if fallback == nil {
+		fallback = http.NotFoundHandler()
+	}
+	return &Router{
+		routes:   map[string]http.Handler{},
+		fallback: fallback,
+	}
+}
+
+// Handle registers h for the resource path, exactly as configured in API Gateway, e.g. "/items/{id}". The pattern may
+// be prefixed with a method, e.g. "GET /items/{id}", which takes precedence over a pattern without one. The ANY method,
+// e.g. "ANY /items/{id}", is the same as no method. Handle panics if a handler is already registered for pattern.
+func (rt *Router) Handle(pattern string, h http.Handler) {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+
+	key := strings.TrimPrefix(pattern, "ANY ")
+	if _, ok := rt.routes[key]; ok {
+		panic("restadapter: multiple registrations for " + pattern)
+	}
+	rt.routes[key] = h
+}
+
+// HandleFunc registers fn for the resource path. See Handle for details.
+func (rt *Router) HandleFunc(pattern string, fn func(http.ResponseWriter, *http.Request)) {
+	rt.Handle(pattern, http.HandlerFunc(fn))
+}
+
+// ServeHTTP implements http.Handler.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.handler(r).ServeHTTP(w, r)
+}
+
+func (rt *Router) handler(r *http.Request) http.Handler {
+	info, ok := PathInfoFrom(r.Context())
+	if !ok || info.Template == "" {
+		return rt.fallback
+	}
+
+	rt.mu.RLock()
+	defer rt.mu.RUnlock()
+
+	if h, ok := rt.routes[r.Method+" "+info.Template]; ok {
+		return h
+	}
+	if h, ok := rt.routes[info.Template]; ok {
+		return h
+	}
+	return rt.fallback
+}
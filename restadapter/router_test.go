@@ -0,0 +1,96 @@
+package restadapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	rt := NewRouter(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "fallback")
+	}))
+	rt.HandleFunc("/items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "item %s %s", r.Method, r.PathValue("id"))
+	})
+	rt.HandleFunc("DELETE /items/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "delete item %s", r.PathValue("id"))
+	})
+	rt.HandleFunc("ANY /items", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "items %s", r.Method)
+	})
+
+	h := Handler(rt)
+	for name, tc := range map[string]struct {
+		resource   string
+		method     string
+		path       string
+		pathParams map[string]string
+		want       string
+	}{
+		"AnyMethod": {"/items/{id}", "GET", "/items/1", map[string]string{"id": "1"}, "item GET 1"},
+		"Method":    {"/items/{id}", "DELETE", "/items/1", map[string]string{"id": "1"}, "delete item 1"},
+		"ANY":       {"/items", "POST", "/items", nil, "items POST"},
+		"Proxy":     {"/{proxy+}", "GET", "/other", map[string]string{"proxy": "other"}, "fallback"},
+		"Unknown":   {"", "GET", "/other", nil, "fallback"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			res, err := h(context.Background(), Request{
+				Resource:       tc.resource,
+				Path:           tc.path,
+				HTTPMethod:     tc.method,
+				PathParameters: tc.pathParams,
+				RequestContext: RequestContext{
+					DomainName: "example.com",
+				},
+			})
+			if !assert.NoError(t, err, "failed to handle request") {
+				return
+			}
+
+			assert.Equal(t, tc.want, res.Body)
+		})
+	}
+}
+
+func TestRouter_ResourcePath(t *testing.T) {
+	rt := NewRouter(nil)
+	rt.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprint(w, "items")
+	})
+
+	// FYI: Requests without a Resource, e.g. built by hand, are routed on RequestContext.ResourcePath.
+	res, err := Handler(rt)(context.Background(), Request{
+		Path:       "/items",
+		HTTPMethod: "GET",
+		RequestContext: RequestContext{
+			DomainName:   "example.com",
+			ResourcePath: "/items",
+		},
+	})
+	if !assert.NoError(t, err, "failed to handle request") {
+		return
+	}
+
+	assert.Equal(t, "items", res.Body)
+}
+
+func TestRouter_NoRequestContext(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewRouter(nil).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, 404, rec.Code)
+}
+
+func TestRouter_DuplicateRoute(t *testing.T) {
+	rt := NewRouter(nil)
+	rt.Handle("/items", http.NotFoundHandler())
+
+	assert.PanicsWithValue(t, "restadapter: multiple registrations for ANY /items", func() {
+		rt.Handle("ANY /items", http.NotFoundHandler())
+	})
+}
@@ -0,0 +1,26 @@
+package wsadapter
+
+import "context"
+
+type contextKey int
+
+const requestContextKey contextKey = iota
+
+func withRequestContext(ctx context.Context, reqCtx RequestContext) context.Context {
+	return context.WithValue(ctx, requestContextKey, reqCtx)
+}
+
+// RequestContextFrom returns the RequestContext of the Request from the context of a transformed http.Request.
+func RequestContextFrom(ctx context.Context) (RequestContext, bool) {
+	reqCtx, ok := ctx.Value(requestContextKey).(RequestContext)
+	return reqCtx, ok
+}
+
+// ConnectionIDFrom returns the WebSocket connection id from the context of a transformed http.Request.
+func ConnectionIDFrom(ctx context.Context) (string, bool) {
+	reqCtx, ok := RequestContextFrom(ctx)
+	if !ok {
+		return "", false
+	}
+	return reqCtx.ConnectionID, true
+}
@@ -0,0 +1,16 @@
+package wsadapter
+
+import "harrisonhjones.com/go-apigw-http-adapter/internal/response"
+
+// EncodingPolicy decides whether TransformResponse base64 encodes the body of a WebSocket API response.
+type EncodingPolicy = response.EncodingPolicy
+
+// BinaryMediaTypes returns an EncodingPolicy which encodes messages whose Content-Type matches one of mediaTypes,
+// e.g. "application/octet-stream".
+func BinaryMediaTypes(mediaTypes ...string) EncodingPolicy {
+	return response.BinaryMediaTypes(mediaTypes...)
+}
+
+// DefaultEncodingPolicy is used by Handler unless WithEncoding is given. It encodes every message which is not text,
+// e.g. an image or a gzip-encoded body.
+var DefaultEncodingPolicy EncodingPolicy = response.DefaultEncodingPolicy
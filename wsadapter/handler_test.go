@@ -0,0 +1,118 @@
+package wsadapter
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler_Routes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/$connect", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, EventTypeConnect, r.Method)
+		if r.URL.Query().Get("token") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/$disconnect", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, EventTypeDisconnect, r.Method)
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/$default", func(w http.ResponseWriter, r *http.Request) {
+		connID, _ := ConnectionIDFrom(r.Context())
+		b, _ := ioutil.ReadAll(r.Body)
+		_, _ = fmt.Fprintf(w, "%s said %s", connID, b)
+	})
+
+	h := Handler(mux)
+
+	t.Run("ConnectAccepted", func(t *testing.T) {
+		res, err := h(context.Background(), Request{
+			MultiValueQueryStringParameters: map[string][]string{"token": {"secret"}},
+			RequestContext:                  RequestContext{RouteKey: RouteKeyConnect, EventType: EventTypeConnect, ConnectionID: "conn-1"},
+		})
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, &Response{StatusCode: 200, MultiValueHeaders: map[string][]string{}}, res)
+	})
+
+	t.Run("ConnectRejected", func(t *testing.T) {
+		res, err := h(context.Background(), Request{
+			RequestContext: RequestContext{RouteKey: RouteKeyConnect, EventType: EventTypeConnect, ConnectionID: "conn-1"},
+		})
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, 403, res.StatusCode)
+	})
+
+	t.Run("Message", func(t *testing.T) {
+		res, err := h(context.Background(), Request{
+			RequestContext: RequestContext{RouteKey: RouteKeyDefault, EventType: EventTypeMessage, ConnectionID: "conn-1"},
+			Body:           "hello",
+		})
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, "conn-1 said hello", res.Body)
+	})
+
+	t.Run("Disconnect", func(t *testing.T) {
+		res, err := h(context.Background(), Request{
+			RequestContext: RequestContext{RouteKey: RouteKeyDisconnect, EventType: EventTypeDisconnect, ConnectionID: "conn-1"},
+		})
+		if !assert.NoError(t, err, "failed to handle request") {
+			return
+		}
+
+		assert.Equal(t, 200, res.StatusCode)
+	})
+}
+
+func TestHandler_TransformRequestError(t *testing.T) {
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("handler should not be called")
+	})
+
+	_, err := Handler(h)(context.Background(), Request{})
+
+	assert.EqualError(t, err, "routeKey and eventType are required")
+}
+
+func TestHandlerFunc_Invoke(t *testing.T) {
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintf(w, "%s %s", r.Method, r.URL.Path)
+	}))
+
+	res, err := h.Invoke(context.Background(), []byte(`{
+		"requestContext": {
+			"routeKey": "$default",
+			"eventType": "MESSAGE",
+			"connectionId": "conn-1",
+			"domainName": "abcdef1234.execute-api.us-east-1.amazonaws.com"
+		},
+		"body": "hello",
+		"isBase64Encoded": false
+	}`))
+	if !assert.NoError(t, err, "failed to invoke handler") {
+		return
+	}
+
+	assert.JSONEq(t,
+		`{
+			"statusCode": 200,
+			"multiValueHeaders": {"Content-Type": ["text/plain; charset=utf-8"]},
+			"body": "MESSAGE /$default"
+		}`,
+		string(res))
+}
@@ -0,0 +1,126 @@
+// Package wsadapter transforms AWS API Gateway WebSocket API Lambda requests and responses to Go HTTP requests and
+// responses.
+//
+// The request method is the event type (CONNECT, MESSAGE or DISCONNECT) and the request path is the route key prefixed
+// with a slash, e.g. "/$connect", "/$default" or "/sendMessage", so route events can be served by an ordinary router.
+// The connection id and the rest of the request context are available from the request context using
+// ConnectionIDFrom and RequestContextFrom.
+package wsadapter
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"harrisonhjones.com/go-apigw-http-adapter/internal/request"
+)
+
+// Event types of WebSocket API requests. They are used as the http.Request method.
+const (
+	EventTypeConnect    = "CONNECT"
+	EventTypeMessage    = "MESSAGE"
+	EventTypeDisconnect = "DISCONNECT"
+)
+
+// Predefined WebSocket API route keys.
+const (
+	RouteKeyConnect    = "$connect"
+	RouteKeyDisconnect = "$disconnect"
+	RouteKeyDefault    = "$default"
+)
+
+// Request contains all relevant API Gateway WebSocket API request data needed to transform it into a http.Request.
+// Headers and query string parameters are only sent for $connect (and some $disconnect) requests. When set,
+// MultiValueHeaders and MultiValueQueryStringParameters always contain all Headers and QueryStringParameters so those
+// can be safely ignored.
+// https://docs.aws.amazon.com/apigateway/latest/developerguide/apigateway-websocket-api-mapping-template-reference.html
+type Request struct {
+	Headers                         map[string]string   `json:"headers,omitempty"`
+	MultiValueHeaders               map[string][]string `json:"multiValueHeaders,omitempty"`
+	QueryStringParameters           map[string]string   `json:"queryStringParameters,omitempty"`
+	MultiValueQueryStringParameters map[string][]string `json:"multiValueQueryStringParameters,omitempty"`
+	RequestContext                  RequestContext      `json:"requestContext"`
+	Body                            string              `json:"body,omitempty"`
+	IsBase64Encoded                 bool                `json:"isBase64Encoded"`
+}
+
+// RequestContext contains the WebSocket route and connection data of the Request.
+type RequestContext struct {
+	RouteKey             string                 `json:"routeKey"`
+	EventType            string                 `json:"eventType"`
+	ConnectionID         string                 `json:"connectionId"`
+	ConnectedAt          int64                  `json:"connectedAt"`
+	MessageID            string                 `json:"messageId,omitempty"`
+	MessageDirection     string                 `json:"messageDirection"`
+	DisconnectStatusCode int                    `json:"disconnectStatusCode,omitempty"`
+	DisconnectReason     string                 `json:"disconnectReason,omitempty"`
+	DomainName           string                 `json:"domainName"`
+	Stage                string                 `json:"stage"`
+	APIID                string                 `json:"apiId"`
+	RequestID            string                 `json:"requestId"`
+	ExtendedRequestID    string                 `json:"extendedRequestId"`
+	RequestTime          string                 `json:"requestTime"`
+	RequestTimeEpoch     int64                  `json:"requestTimeEpoch"`
+	Identity             RequestContextIdentity `json:"identity"`
+}
+
+// RequestContextIdentity contains the identity of the client.
+type RequestContextIdentity struct {
+	SourceIP  string `json:"sourceIp"`
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+// TransformRequest transforms a *Request to a *http.Request.
+// The RequestContext is stored in the context of the *http.Request.
+// A non-nil error will be returned if the *Request is nil or if the transformation fails.
+// The *Request will not be mutated during transformation.
+func TransformRequest(ctx context.Context, req *Request) (*http.Request, error) {
+	if req == nil {
+		return nil, fmt.Errorf("req cannot be nil")
+	}
+
+	if req.RequestContext.RouteKey == "" || req.RequestContext.EventType == "" {
+		return nil, fmt.Errorf("routeKey and eventType are required")
+	}
+
+	body, err := request.Body(req.Body, req.IsBase64Encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	u := &url.URL{
+		Scheme: "https",
+		Host:   req.RequestContext.DomainName,
+		Path:   "/" + req.RequestContext.RouteKey,
+	}
+
+	qValues := url.Values{}
+	if req.MultiValueQueryStringParameters != nil {
+		for k, parts := range req.MultiValueQueryStringParameters {
+			for _, part := range parts {
+				qValues.Add(k, part)
+			}
+		}
+	} else {
+		for k, v := range req.QueryStringParameters {
+			qValues.Add(k, v)
+		}
+	}
+	u.RawQuery = qValues.Encode()
+
+	hReq, err := http.NewRequest(req.RequestContext.EventType, u.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new http request: %v", err)
+	}
+
+	hReq = hReq.WithContext(withRequestContext(ctx, req.RequestContext))
+
+	if req.MultiValueHeaders != nil {
+		request.AddMultiValueHeaders(hReq.Header, req.MultiValueHeaders)
+	} else {
+		request.AddHeaders(hReq.Header, req.Headers, nil)
+	}
+
+	return hReq, nil
+}
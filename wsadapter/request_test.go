@@ -0,0 +1,136 @@
+package wsadapter
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformRequest_Connect(t *testing.T) {
+	req := Request{
+		Headers: map[string]string{
+			"Host":                   "abcdef1234.execute-api.us-east-1.amazonaws.com",
+			"Sec-WebSocket-Protocol": "chat",
+		},
+		MultiValueHeaders: map[string][]string{
+			"Host":                   {"abcdef1234.execute-api.us-east-1.amazonaws.com"},
+			"Sec-WebSocket-Protocol": {"chat"},
+		},
+		QueryStringParameters: map[string]string{
+			"token": "abc",
+		},
+		MultiValueQueryStringParameters: map[string][]string{
+			"token": {"abc"},
+		},
+		RequestContext: RequestContext{
+			RouteKey:         "$connect",
+			EventType:        "CONNECT",
+			ConnectionID:     "L0SM9cOFvHcCIhw=",
+			ConnectedAt:      1583348638390,
+			MessageDirection: "IN",
+			DomainName:       "abcdef1234.execute-api.us-east-1.amazonaws.com",
+			Stage:            "prod",
+			APIID:            "abcdef1234",
+			RequestID:        "L0SM9GXnvHcF9ew=",
+			Identity:         RequestContextIdentity{SourceIP: "192.0.2.1"},
+		},
+	}
+
+	tstCtx := context.Background()
+
+	httpReq, err := TransformRequest(tstCtx, &req)
+	if !assert.NoError(t, err, "failed to transform request") {
+		return
+	}
+
+	assert.Equal(t, "CONNECT", httpReq.Method)
+	assert.Equal(t, "https://abcdef1234.execute-api.us-east-1.amazonaws.com/$connect?token=abc", httpReq.URL.String())
+	assert.Equal(t,
+		http.Header{
+			"Host":                   {"abcdef1234.execute-api.us-east-1.amazonaws.com"},
+			"Sec-Websocket-Protocol": {"chat"},
+		},
+		httpReq.Header)
+
+	connID, ok := ConnectionIDFrom(httpReq.Context())
+	assert.True(t, ok, "connection id should be in the context")
+	assert.Equal(t, "L0SM9cOFvHcCIhw=", connID)
+
+	reqCtx, ok := RequestContextFrom(httpReq.Context())
+	assert.True(t, ok, "request context should be in the context")
+	assert.Equal(t, req.RequestContext, reqCtx)
+}
+
+func TestTransformRequest_Message(t *testing.T) {
+	req := Request{
+		RequestContext: RequestContext{
+			RouteKey:     "sendMessage",
+			EventType:    "MESSAGE",
+			ConnectionID: "L0SM9cOFvHcCIhw=",
+			MessageID:    "L0SNBfnzvHcCIhw=",
+			DomainName:   "abcdef1234.execute-api.us-east-1.amazonaws.com",
+		},
+	}
+
+	tstCtx := context.Background()
+
+	t.Run("NotEncoded", func(t *testing.T) {
+		req.Body = `{"action":"sendMessage","data":"Hello World!"}`
+		req.IsBase64Encoded = false
+
+		httpReq, err := TransformRequest(tstCtx, &req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		assert.Equal(t, "MESSAGE", httpReq.Method)
+		assert.Equal(t, "/sendMessage", httpReq.URL.Path)
+		assert.Equal(t, http.Header{}, httpReq.Header)
+
+		b, err := ioutil.ReadAll(httpReq.Body)
+		if !assert.NoError(t, err, "failed to read body") {
+			return
+		}
+		assert.Equal(t, []byte(`{"action":"sendMessage","data":"Hello World!"}`), b)
+	})
+
+	t.Run("Encoded", func(t *testing.T) {
+		req.Body = "SGVsbG8gRW5jb2RlZCBXb3JsZCE=" // FYI: base64.StdEncoding.EncodeToString([]byte("Hello Encoded World!"))
+		req.IsBase64Encoded = true
+
+		httpReq, err := TransformRequest(tstCtx, &req)
+		if !assert.NoError(t, err, "failed to transform request") {
+			return
+		}
+
+		b, err := ioutil.ReadAll(httpReq.Body)
+		if !assert.NoError(t, err, "failed to read body") {
+			return
+		}
+		assert.Equal(t, []byte("Hello Encoded World!"), b)
+	})
+
+	t.Run("IncorrectlyEncoded", func(t *testing.T) {
+		req.Body = "blarg"
+		req.IsBase64Encoded = true
+
+		_, err := TransformRequest(tstCtx, &req)
+
+		assert.EqualError(t, err, "failed to decode body: illegal base64 data at input byte 4")
+	})
+}
+
+func TestTransformRequest_NotWebSocketRequest(t *testing.T) {
+	_, err := TransformRequest(context.Background(), &Request{})
+
+	assert.EqualError(t, err, "routeKey and eventType are required")
+}
+
+func TestConnectionIDFrom_Missing(t *testing.T) {
+	_, ok := ConnectionIDFrom(context.Background())
+
+	assert.False(t, ok)
+}
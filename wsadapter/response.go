@@ -0,0 +1,50 @@
+package wsadapter
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"harrisonhjones.com/go-apigw-http-adapter/internal/response"
+)
+
+// Response configures the response to be returned by the API Gateway WebSocket API for the request.
+// For $connect routes a non-2xx StatusCode rejects the connection. For other routes the Body is sent to the client
+// if a route response is configured.
+// MultiValueHeaders are only used by $connect routes, e.g. to select a subprotocol using Sec-WebSocket-Protocol.
+// https://docs.aws.amazon.com/apigateway/latest/developerguide/apigateway-websocket-api-route-response.html
+type Response struct {
+	StatusCode        int                 `json:"statusCode"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders,omitempty"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded,omitempty"`
+}
+
+// TransformResponse transforms an http.Response to a Response.
+// The body is base64 encoded if encRes reports true for res, e.g. DefaultEncodingPolicy, or if it is not valid UTF-8.
+// A nil encRes only encodes bodies which are not valid UTF-8.
+func TransformResponse(res *http.Response, encRes EncodingPolicy) (*Response, error) {
+	apigwRes := &Response{
+		StatusCode:        res.StatusCode,
+		MultiValueHeaders: map[string][]string{},
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	apigwRes.Body, apigwRes.IsBase64Encoded, err = response.EncodeBody(body, encRes != nil && encRes(res), response.UTF8Auto)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range res.Header {
+		if len(v) == 0 {
+			continue
+		}
+		apigwRes.MultiValueHeaders[k] = append([]string(nil), v...)
+	}
+
+	return apigwRes, nil
+}